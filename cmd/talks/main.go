@@ -0,0 +1,13 @@
+// Command talks manages the talks repository: it creates new talks,
+// validates metadata, regenerates the README index and reports statistics.
+package main
+
+import (
+	"os"
+
+	"github.com/shankyjs/talks/internal/cli"
+)
+
+func main() {
+	os.Exit(cli.Run(os.Args[1:], os.Stdout, os.Stderr))
+}
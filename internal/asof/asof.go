@@ -0,0 +1,84 @@
+// Package asof resolves the date generated files are computed "as of", which
+// decides whether a talk is past or upcoming.
+//
+// Using a fixed date instead of the clock makes the output reproducible: the
+// same commit renders the same README on any machine and on any day.
+package asof
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Layout is the format of as-of dates, as used in metadata.yaml.
+const Layout = "2006-01-02"
+
+// EpochEnv is the environment variable of the reproducible builds
+// convention: a Unix timestamp to use instead of the current time.
+const EpochEnv = "SOURCE_DATE_EPOCH"
+
+// Source tells where a resolved date came from.
+type Source string
+
+const (
+	SourceFlag   Source = "--as-of"
+	SourceEpoch  Source = EpochEnv
+	SourceCommit Source = "latest commit"
+	SourceClock  Source = "current date"
+)
+
+// Resolve returns the as-of date (YYYY-MM-DD, in UTC) for the repository at
+// root, from the first of: value (the --as-of flag), SOURCE_DATE_EPOCH, the
+// committer date of the latest git commit, and the current date when root
+// is not a git repository.
+func Resolve(root, value string) (string, Source, error) {
+	if value != "" {
+		date, err := time.Parse(Layout, value)
+		if err != nil {
+			return "", "", fmt.Errorf("invalid --as-of date %q (use YYYY-MM-DD)", value)
+		}
+		return date.Format(Layout), SourceFlag, nil
+	}
+
+	if epoch := os.Getenv(EpochEnv); epoch != "" {
+		date, err := parseEpoch(epoch)
+		if err != nil {
+			return "", "", fmt.Errorf("invalid %s %q: %w", EpochEnv, epoch, err)
+		}
+		return date, SourceEpoch, nil
+	}
+
+	if date, ok := latestCommit(root); ok {
+		return date, SourceCommit, nil
+	}
+
+	return time.Now().UTC().Format(Layout), SourceClock, nil
+}
+
+func parseEpoch(s string) (string, error) {
+	seconds, err := strconv.ParseInt(strings.TrimSpace(s), 10, 64)
+	if err != nil {
+		return "", err
+	}
+	return time.Unix(seconds, 0).UTC().Format(Layout), nil
+}
+
+// latestCommit returns the committer date of HEAD in root.
+func latestCommit(root string) (string, bool) {
+	cmd := exec.Command("git", "log", "-1", "--format=%ct")
+	cmd.Dir = root
+	out, err := cmd.Output()
+	if err != nil {
+		return "", false
+	}
+
+	date, err := parseEpoch(string(out))
+	if err != nil {
+		return "", false
+	}
+	return date, true
+}
@@ -0,0 +1,469 @@
+// Package check validates the talk directories and their metadata files.
+package check
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/shankyjs/talks/internal/config"
+	"github.com/shankyjs/talks/internal/scaffold"
+	"github.com/shankyjs/talks/internal/schema"
+	"github.com/shankyjs/talks/internal/talkrepo"
+)
+
+// Severity is how serious a finding is. Errors fail the check.
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// Rule IDs of the findings.
+const (
+	RuleMissingMetadata = "missing-metadata"
+	RuleReadError       = "read-error"
+	RuleParseError      = "parse-error"
+	RuleSchema          = "schema"
+	RuleMissingReadme   = "missing-readme"
+	RuleUnknownField    = "unknown-field"
+	RuleInvalidDate     = "invalid-date"
+	RuleDateMismatch    = "date-mismatch"
+	RuleInvalidTime     = "invalid-time"
+	RulePlaceholder     = "placeholder"
+	RuleUnknownTopic    = "unknown-topic"
+	RuleTopicAlias      = "topic-alias"
+	RuleMissingLanguage = "missing-translation"
+	RuleUnknownLanguage = "unknown-language"
+)
+
+// Rules describes every rule, keyed by ID.
+var Rules = map[string]string{
+	RuleMissingMetadata: "Talk directory has no metadata.yaml",
+	RuleReadError:       "File cannot be read",
+	RuleParseError:      "metadata.yaml is not valid YAML or does not match the metadata fields",
+	RuleSchema:          "metadata.yaml does not match the metadata JSON Schema",
+	RuleMissingReadme:   "Talk directory is missing a README for a configured language",
+	RuleUnknownField:    "metadata.yaml has a field that is neither required nor optional",
+	RuleInvalidDate:     "Talk date is not a valid calendar date",
+	RuleDateMismatch:    "Talk directory does not match the talk date",
+	RuleInvalidTime:     "Talk time or time zone is not valid",
+	RulePlaceholder:     "Scaffold placeholder has not been replaced",
+	RuleUnknownTopic:    "Topic is not in the topic registry",
+	RuleTopicAlias:      "Topic is not written with its canonical name from the topic registry",
+	RuleMissingLanguage: "Localized metadata field has no value for a configured language",
+	RuleUnknownLanguage: "Localized metadata field has a value for a language that is not configured",
+}
+
+// Finding is a single problem found in the repository.
+type Finding struct {
+	Rule     string   `json:"rule"`
+	Severity Severity `json:"severity"`
+	File     string   `json:"file"`             // relative to the root
+	Line     int      `json:"line,omitempty"`   // 1-based, 0 when unknown
+	Column   int      `json:"column,omitempty"` // 1-based, 0 when unknown
+	Message  string   `json:"message"`
+}
+
+// Location returns the file with the line and column, when known.
+func (f Finding) Location() string {
+	loc := f.File
+	if f.Line > 0 {
+		loc += ":" + strconv.Itoa(f.Line)
+		if f.Column > 0 {
+			loc += ":" + strconv.Itoa(f.Column)
+		}
+	}
+	return loc
+}
+
+func (f Finding) String() string {
+	return f.Location() + ": " + f.Message
+}
+
+// Result holds the findings for the repository, in discovery order.
+type Result struct {
+	Findings []Finding
+}
+
+// Errors returns the findings with error severity.
+func (r Result) Errors() []Finding {
+	return r.filter(SeverityError)
+}
+
+// Warnings returns the findings with warning severity.
+func (r Result) Warnings() []Finding {
+	return r.filter(SeverityWarning)
+}
+
+func (r Result) filter(severity Severity) []Finding {
+	var out []Finding
+	for _, f := range r.Findings {
+		if f.Severity == severity {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+// OK reports whether no errors were found.
+func (r Result) OK() bool {
+	return len(r.Errors()) == 0
+}
+
+// position is a line and column in a file.
+type position struct {
+	line, column int
+}
+
+func nodePosition(node *yaml.Node) position {
+	if node == nil {
+		return position{}
+	}
+	return position{node.Line, node.Column}
+}
+
+// checker holds what is shared by the checks of every talk.
+type checker struct {
+	root   string
+	cfg    *config.Config
+	schema *schema.Schema
+	today  string
+	result Result
+}
+
+// Run checks every talk directory under root against cfg and the metadata
+// schema. Placeholders left in talks dated before asOf (YYYY-MM-DD) are
+// errors, in later ones warnings.
+func Run(root string, cfg *config.Config, asOf string) (Result, error) {
+	s, err := schema.Metadata(cfg)
+	if err != nil {
+		return Result{}, err
+	}
+	c := &checker{root: root, cfg: cfg, schema: s, today: asOf}
+
+	// Find all talk directories
+	dirs, err := talkrepo.FindTalkDirs(root)
+	if err != nil {
+		return Result{}, err
+	}
+
+	for _, dir := range dirs {
+		c.checkTalk(filepath.ToSlash(dir))
+	}
+
+	return c.result, nil
+}
+
+func (c *checker) add(severity Severity, rule, file string, pos position, format string, args ...any) {
+	c.result.Findings = append(c.result.Findings, Finding{
+		Rule:     rule,
+		Severity: severity,
+		File:     file,
+		Line:     pos.line,
+		Column:   pos.column,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+func (c *checker) errorf(rule, file string, pos position, format string, args ...any) {
+	c.add(SeverityError, rule, file, pos, format, args...)
+}
+
+func (c *checker) warnf(rule, file string, pos position, format string, args ...any) {
+	c.add(SeverityWarning, rule, file, pos, format, args...)
+}
+
+// yamlErrorLine extracts the line number from a yaml.v3 error message.
+var yamlErrorLine = regexp.MustCompile(`line ([0-9]+):`)
+
+func (c *checker) checkTalk(talkPath string) {
+	metadataPath := path.Join(talkPath, talkrepo.MetadataFile)
+
+	talk, err := talkrepo.LoadTalk(c.root, talkPath)
+	switch {
+	case errors.Is(err, talkrepo.ErrMissingMetadata):
+		c.errorf(RuleMissingMetadata, metadataPath, position{}, "missing metadata.yaml in %s", talkPath)
+		return
+	case errors.Is(err, talkrepo.ErrReadMetadata):
+		c.errorf(RuleReadError, metadataPath, position{}, "cannot read file: %v", errors.Unwrap(err))
+		return
+	}
+
+	// Check if READMEs exist
+	for _, lang := range c.cfg.Languages {
+		readmePath := path.Join(talkPath, lang.TalkReadme)
+		if _, err := os.Stat(filepath.Join(c.root, readmePath)); os.IsNotExist(err) {
+			c.warnf(RuleMissingReadme, readmePath, position{}, "missing %s in %s", lang.TalkReadme, talkPath)
+		}
+	}
+
+	// Validate metadata content against the schema
+	data, readErr := os.ReadFile(filepath.Join(c.root, metadataPath))
+	if readErr != nil {
+		c.errorf(RuleReadError, metadataPath, position{}, "cannot read file: %v", readErr)
+		return
+	}
+
+	violations, node, parseErr := c.schema.ValidateYAML(data)
+	if parseErr != nil {
+		var pos position
+		if m := yamlErrorLine.FindStringSubmatch(parseErr.Error()); m != nil {
+			pos.line, _ = strconv.Atoi(m[1])
+		}
+		c.errorf(RuleParseError, metadataPath, pos, "cannot parse YAML: %v", parseErr)
+		return
+	}
+	for _, v := range violations {
+		c.errorf(RuleSchema, metadataPath, position{v.Line, v.Column}, "%s", v)
+	}
+
+	// Anything the schema accepts should also load; report it otherwise
+	if len(violations) == 0 && errors.Is(err, talkrepo.ErrParseMetadata) {
+		c.errorf(RuleParseError, metadataPath, position{}, "cannot load metadata: %v", errors.Unwrap(err))
+		return
+	}
+
+	if len(violations) == 0 {
+		c.checkDate(talk, node)
+		c.checkTime(talk, node)
+		c.checkPlaceholders(talk, node)
+		c.checkTopics(talk, node)
+		c.checkTranslations(talk, node)
+	}
+
+	// Check for fields the configuration does not know about
+	for _, key := range c.unknownFields(node) {
+		c.warnf(RuleUnknownField, metadataPath, nodePosition(key), "unknown field '%s'", key.Value)
+	}
+}
+
+// datePrefix matches the month-day prefix of a talk directory name, as
+// produced by scaffold.FormatMonthDay (e.g. "feb-25th-").
+var datePrefix = regexp.MustCompile(`^[a-z]{3}-[0-9]{1,2}(st|nd|rd|th)-`)
+
+// checkDate verifies that the talk date is a real calendar date and that
+// the talk lives in the directory create-talk would have used for it.
+func (c *checker) checkDate(talk talkrepo.Talk, node *yaml.Node) {
+	metadataPath := path.Join(talk.Path, talkrepo.MetadataFile)
+	pos := nodePosition(mappingValue(node, "date"))
+
+	date, err := time.Parse("2006-01-02", talk.Date)
+	if err != nil {
+		c.errorf(RuleInvalidDate, metadataPath, pos, "date: %q is not a valid date", talk.Date)
+		return
+	}
+
+	name := path.Base(talk.Path)
+	year := date.Format("2006")
+	prefix := scaffold.FormatMonthDay(date) + "-"
+	if talk.Year == year && strings.HasPrefix(name, prefix) {
+		return
+	}
+
+	slug := strings.TrimPrefix(name, datePrefix.FindString(name))
+	expected := path.Join(year, prefix+slug)
+	if talk.Year != year {
+		c.errorf(RuleDateMismatch, metadataPath, pos, "date %s does not match year directory %s (expected %s)", talk.Date, talk.Year, expected)
+	} else {
+		c.errorf(RuleDateMismatch, metadataPath, pos, "date %s does not match directory name %s (expected %s)", talk.Date, name, expected)
+	}
+}
+
+// checkTime verifies that the optional start time is a real time of day
+// and that its time zone is known, as the calendar export needs both.
+func (c *checker) checkTime(talk talkrepo.Talk, node *yaml.Node) {
+	metadataPath := path.Join(talk.Path, talkrepo.MetadataFile)
+
+	if talk.Time != "" {
+		if _, err := time.Parse("15:04", talk.Time); err != nil {
+			c.errorf(RuleInvalidTime, metadataPath, nodePosition(mappingValue(node, "time")), "time: %q is not a valid HH:MM time", talk.Time)
+		}
+	}
+	if talk.Timezone != "" {
+		if _, err := time.LoadLocation(talk.Timezone); err != nil {
+			c.errorf(RuleInvalidTime, metadataPath, nodePosition(mappingValue(node, "timezone")), "timezone: %q is not a known time zone", talk.Timezone)
+		}
+	}
+}
+
+// checkPlaceholders reports scaffold placeholders left in the metadata and
+// in the talk READMEs. They are expected before a talk happens, so they are
+// only errors once its date has passed.
+func (c *checker) checkPlaceholders(talk talkrepo.Talk, node *yaml.Node) {
+	report := c.warnf
+	if talk.Date < c.today {
+		report = c.errorf
+	}
+
+	placeholders := c.cfg.Placeholders
+	metadataPath := path.Join(talk.Path, talkrepo.MetadataFile)
+
+	if talk.Event != "" && c.cfg.IsPlaceholderEvent(talk.Event) {
+		report(RulePlaceholder, metadataPath, nodePosition(mappingValue(node, "event")),
+			"event: placeholder %q has not been replaced", talk.Event)
+	}
+	for _, value := range localizedValues(mappingValue(node, "description")) {
+		if value.Value != "" && value.Value == placeholders.Description {
+			report(RulePlaceholder, metadataPath, nodePosition(value),
+				"description: placeholder %q has not been replaced", value.Value)
+		}
+	}
+	if topics := mappingValue(node, "topics"); topics != nil {
+		for i, topic := range topics.Content {
+			if c.cfg.IsPlaceholderTopic(topic.Value) {
+				report(RulePlaceholder, metadataPath, nodePosition(topic),
+					"topics[%d]: placeholder %q has not been replaced", i, topic.Value)
+			}
+		}
+	}
+
+	// The scaffolded metadata is rendered into the READMEs as well
+	phrases := append([]string{placeholders.Event(), placeholders.Description}, placeholders.Readme...)
+
+	for _, lang := range c.cfg.Languages {
+		readmePath := path.Join(talk.Path, lang.TalkReadme)
+		data, err := os.ReadFile(filepath.Join(c.root, readmePath))
+		if err != nil {
+			continue
+		}
+
+		for i, line := range strings.Split(string(data), "\n") {
+			for _, phrase := range phrases {
+				if col := strings.Index(line, phrase); phrase != "" && col >= 0 {
+					report(RulePlaceholder, readmePath, position{i + 1, utf8.RuneCountInString(line[:col]) + 1},
+						"placeholder %q has not been replaced", phrase)
+					break
+				}
+			}
+		}
+	}
+}
+
+// checkTopics reports topics missing from the topic registry and topics
+// written as an alias instead of their canonical name. Without a registry
+// every topic is accepted as written.
+func (c *checker) checkTopics(talk talkrepo.Talk, node *yaml.Node) {
+	registry := c.cfg.Topics
+	topics := mappingValue(node, "topics")
+	if registry.Len() == 0 || topics == nil {
+		return
+	}
+
+	metadataPath := path.Join(talk.Path, talkrepo.MetadataFile)
+	for i, topic := range topics.Content {
+		if topic.Value == "" || c.cfg.IsPlaceholderTopic(topic.Value) {
+			continue
+		}
+
+		t, ok := registry.Lookup(topic.Value)
+		switch {
+		case !ok:
+			c.warnf(RuleUnknownTopic, metadataPath, nodePosition(topic),
+				"topics[%d]: unknown topic %q (add it to %s)", i, topic.Value, c.cfg.Taxonomy)
+		case topic.Value != t.Name:
+			c.warnf(RuleTopicAlias, metadataPath, nodePosition(topic),
+				"topics[%d]: %q should be written as %q", i, topic.Value, t.Name)
+		}
+	}
+}
+
+// localizedFields are the metadata fields that accept a value per language.
+var localizedFields = []string{"title", "description"}
+
+// checkTranslations reports localized fields that miss a configured
+// language or have a value for a language that is not configured.
+func (c *checker) checkTranslations(talk talkrepo.Talk, node *yaml.Node) {
+	metadataPath := path.Join(talk.Path, talkrepo.MetadataFile)
+	for _, field := range localizedFields {
+		value := mappingValue(node, field)
+		if value == nil || value.Kind != yaml.MappingNode {
+			continue
+		}
+
+		for _, lang := range c.cfg.Languages {
+			if text := mappingValue(value, lang.Code); text == nil || text.Value == "" {
+				c.warnf(RuleMissingLanguage, metadataPath, nodePosition(value),
+					"%s: missing %q translation", field, lang.Code)
+			}
+		}
+		for i := 0; i+1 < len(value.Content); i += 2 {
+			if key := value.Content[i]; !c.hasLanguage(key.Value) {
+				c.warnf(RuleUnknownLanguage, metadataPath, nodePosition(key),
+					"%s: unknown language %q", field, key.Value)
+			}
+		}
+	}
+}
+
+func (c *checker) hasLanguage(code string) bool {
+	_, ok := c.cfg.Language(code)
+	return ok
+}
+
+// localizedValues returns the value nodes of a localized field: the node
+// itself for the plain string form, the values of the mapping otherwise.
+func localizedValues(node *yaml.Node) []*yaml.Node {
+	switch {
+	case node == nil:
+		return nil
+	case node.Kind == yaml.MappingNode:
+		var values []*yaml.Node
+		for i := 1; i < len(node.Content); i += 2 {
+			values = append(values, node.Content[i])
+		}
+		return values
+	}
+	return []*yaml.Node{node}
+}
+
+// unknownFields returns the key nodes of the top-level fields of a metadata
+// document that are neither required nor optional, sorted by name.
+func (c *checker) unknownFields(node *yaml.Node) []*yaml.Node {
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	known := make(map[string]bool)
+	for _, field := range c.cfg.Metadata.Required {
+		known[field] = true
+	}
+	for _, field := range c.cfg.Metadata.Optional {
+		known[field] = true
+	}
+
+	var unknown []*yaml.Node
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if key := node.Content[i]; !known[key.Value] {
+			unknown = append(unknown, key)
+		}
+	}
+	sort.Slice(unknown, func(i, j int) bool {
+		return unknown[i].Value < unknown[j].Value
+	})
+	return unknown
+}
+
+// mappingValue returns the value of key in a mapping node, or nil.
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,306 @@
+package check
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/shankyjs/talks/internal/config"
+	"github.com/shankyjs/talks/internal/diff"
+	"github.com/shankyjs/talks/internal/scaffold"
+	"github.com/shankyjs/talks/internal/talkrepo"
+	"github.com/shankyjs/talks/internal/topics"
+)
+
+// Fix is a change to one file that resolves mechanically fixable problems.
+type Fix struct {
+	File    string   // relative to the root
+	Old     []byte   // nil when the file is created
+	New     []byte   // new content
+	Changes []string // what was fixed, one entry per change
+}
+
+// Diff returns the fix as a unified diff.
+func (f Fix) Diff() string {
+	oldName := "a/" + f.File
+	if f.Old == nil {
+		oldName = "/dev/null"
+	}
+	return diff.Unified(oldName, "b/"+f.File, string(f.Old), string(f.New), 3)
+}
+
+// dateLayouts are the date formats fixes understand, besides YYYY-MM-DD.
+var dateLayouts = []string{
+	"2006-1-2",
+	"2006/1/2",
+	"2006.1.2",
+	"2 Jan 2006",
+	"Jan 2, 2006",
+	"January 2, 2006",
+	time.RFC3339,
+}
+
+// Fixes returns the fixes for the mechanically fixable problems under root:
+// missing talk READMEs (created from the templates), unquoted or
+// differently formatted dates, duplicate topics, topic aliases and
+// inconsistently cased topics, and surrounding whitespace in metadata values.
+// Metadata files whose fixes cannot be made without touching other values
+// (see fixMetadata) are left alone. Nothing is written.
+func Fixes(root string, cfg *config.Config) ([]Fix, error) {
+	talks, _, err := talkrepo.Load(root)
+	if err != nil {
+		return nil, err
+	}
+
+	topics := newTopicSpellings(talks, cfg.Topics)
+
+	var fixes []Fix
+	for _, talk := range talks {
+		metadataPath := path.Join(talk.Path, talkrepo.MetadataFile)
+		data, err := os.ReadFile(filepath.Join(root, metadataPath))
+		if err != nil {
+			return nil, err
+		}
+
+		fixed, changes, err := fixMetadata(data, topics)
+		if errors.Is(err, errNotInPlace) {
+			// Left for the user; check keeps reporting the problems
+			changes = nil
+		} else if err != nil {
+			return nil, fmt.Errorf("%s: %w", metadataPath, err)
+		}
+		if len(changes) > 0 {
+			fixes = append(fixes, Fix{File: metadataPath, Old: data, New: fixed, Changes: changes})
+
+			// Render the READMEs from the fixed metadata
+			if err := yaml.Unmarshal(fixed, &talk.Metadata); err != nil {
+				return nil, fmt.Errorf("%s: %w", metadataPath, err)
+			}
+		}
+
+		for _, lang := range cfg.Languages {
+			fix, ok, err := missingReadme(root, talk, lang)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				fixes = append(fixes, fix)
+			}
+		}
+	}
+
+	return fixes, nil
+}
+
+// Apply writes fixes under root.
+func Apply(root string, fixes []Fix) error {
+	for _, fix := range fixes {
+		if err := os.WriteFile(filepath.Join(root, fix.File), fix.New, 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// missingReadme creates the README of lang from the templates when the talk
+// does not have it. Talks without a title in lang are left alone: the
+// README would start with an empty heading, and check keeps reporting both
+// the missing title and the missing README.
+func missingReadme(root string, talk talkrepo.Talk, lang config.Language) (Fix, bool, error) {
+	readmePath := path.Join(talk.Path, lang.TalkReadme)
+	if _, err := os.Stat(filepath.Join(root, readmePath)); !errors.Is(err, os.ErrNotExist) {
+		return Fix{}, false, nil
+	}
+	if !scaffold.HasTemplate(lang.TalkReadme) || strings.TrimSpace(talk.Title.Text(lang.Code)) == "" {
+		return Fix{}, false, nil
+	}
+
+	name := path.Base(talk.Path)
+	content, err := scaffold.Render(lang.TalkReadme, scaffold.TalkData{
+		Title:       talk.Title.Text(lang.Code),
+		Date:        talk.Date,
+		Event:       talk.Event,
+		Description: talk.Description.Text(lang.Code),
+		Topics:      talk.Topics,
+		Slug:        strings.TrimPrefix(name, datePrefix.FindString(name)),
+	})
+	if err != nil {
+		return Fix{}, false, fmt.Errorf("rendering %s: %w", readmePath, err)
+	}
+
+	return Fix{
+		File:    readmePath,
+		New:     content,
+		Changes: []string{fmt.Sprintf("create %s from the template", lang.TalkReadme)},
+	}, true, nil
+}
+
+// topicSpellings picks one spelling for each topic: the canonical name from
+// the registry or, for topics missing from it, the spelling used by most
+// talks, or the first one seen on a tie.
+type topicSpellings map[string]string
+
+func newTopicSpellings(talks []talkrepo.Talk, registry *topics.Registry) topicSpellings {
+	counts := make(map[string]int)
+	spellings := make(topicSpellings)
+	for _, t := range registry.Topics {
+		spellings[strings.ToLower(t.Name)] = t.Name
+		for _, alias := range t.Aliases {
+			spellings[strings.ToLower(alias)] = t.Name
+		}
+	}
+	for _, talk := range talks {
+		for _, topic := range talk.Topics {
+			topic = strings.TrimSpace(topic)
+			counts[topic]++
+			if _, ok := registry.Lookup(topic); ok {
+				continue
+			}
+			key := strings.ToLower(topic)
+			if best, ok := spellings[key]; !ok || counts[topic] > counts[best] {
+				spellings[key] = topic
+			}
+		}
+	}
+	return spellings
+}
+
+func (s topicSpellings) canonical(topic string) string {
+	if spelling, ok := s[strings.ToLower(topic)]; ok {
+		return spelling
+	}
+	return topic
+}
+
+// fixMetadata applies the metadata fixes to a metadata.yaml document and
+// returns the new content with the list of changes. The fixes are found
+// through the yaml.v3 node API and written by editing only the source text
+// of the values they change, so comments, blank lines and the style of
+// every other value are kept byte for byte. It returns errNotInPlace when
+// a change cannot be written that way.
+func fixMetadata(data []byte, topics topicSpellings) ([]byte, []string, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, nil, err
+	}
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return data, nil, nil
+	}
+
+	p := newPatch(data)
+	var changes []string
+	node := doc.Content[0]
+	flow := node.Style&yaml.FlowStyle != 0
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i].Value, node.Content[i+1]
+		switch {
+		case key == "date":
+			changes = append(changes, fixDate(p, value, flow)...)
+		case key == "topics" && value.Kind == yaml.SequenceNode:
+			changes = append(changes, fixTopics(p, value, topics)...)
+		case (key == "title" || key == "description") && value.Kind == yaml.MappingNode:
+			// The mapping form of talkrepo.Localized, one value per language
+			for j := 0; j+1 < len(value.Content); j += 2 {
+				name := key + "." + value.Content[j].Value
+				changes = append(changes, fixSpace(p, value.Content[j+1], value.Style&yaml.FlowStyle != 0, name)...)
+			}
+		default:
+			changes = append(changes, fixSpace(p, value, flow, key)...)
+		}
+	}
+
+	if len(changes) == 0 {
+		return data, nil, nil
+	}
+
+	fixed, err := p.apply()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// The edited text must hold exactly the fixed values
+	var got yaml.Node
+	if err := yaml.Unmarshal(fixed, &got); err != nil || !sameValues(&doc, &got) {
+		return nil, nil, errNotInPlace
+	}
+	return fixed, changes, nil
+}
+
+// fixSpace trims the whitespace around a string value. Block scalars are
+// left alone: their line breaks are part of the value.
+func fixSpace(p *patch, value *yaml.Node, flow bool, name string) []string {
+	if value.Kind != yaml.ScalarNode || value.Tag != "!!str" || value.Style&(yaml.LiteralStyle|yaml.FoldedStyle) != 0 {
+		return nil
+	}
+	trimmed := strings.TrimSpace(value.Value)
+	if trimmed == value.Value {
+		return nil
+	}
+	p.replace(value, flow, func() { value.Value = trimmed })
+	return []string{fmt.Sprintf("%s: trim whitespace", name)}
+}
+
+func fixDate(p *patch, value *yaml.Node, flow bool) []string {
+	if value.Kind != yaml.ScalarNode {
+		return nil
+	}
+
+	var changes []string
+	formatted := value.Value
+	if _, err := time.Parse("2006-01-02", value.Value); err != nil {
+		for _, layout := range dateLayouts {
+			if date, err := time.Parse(layout, strings.TrimSpace(value.Value)); err == nil {
+				formatted = date.Format("2006-01-02")
+				changes = append(changes, fmt.Sprintf("date: reformat %q as %q", value.Value, formatted))
+				break
+			}
+		}
+	}
+
+	if value.Style != yaml.DoubleQuotedStyle {
+		changes = append(changes, "date: quote value")
+	}
+	if len(changes) > 0 {
+		p.replace(value, flow, func() {
+			value.Value = formatted
+			value.Style = yaml.DoubleQuotedStyle
+			value.Tag = "!!str"
+		})
+	}
+	return changes
+}
+
+func fixTopics(p *patch, value *yaml.Node, topics topicSpellings) []string {
+	flow := value.Style&yaml.FlowStyle != 0
+	var changes []string
+	seen := make(map[string]bool)
+	kept := value.Content[:0]
+	for _, item := range value.Content {
+		if item.Kind != yaml.ScalarNode || item.Value == "" {
+			kept = append(kept, item)
+			continue
+		}
+
+		topic := topics.canonical(strings.TrimSpace(item.Value))
+		if seen[strings.ToLower(topic)] {
+			changes = append(changes, fmt.Sprintf("topics: remove duplicate %q", item.Value))
+			p.remove(value, item)
+			continue
+		}
+		seen[strings.ToLower(topic)] = true
+
+		if topic != item.Value {
+			changes = append(changes, fmt.Sprintf("topics: rename %q to %q", item.Value, topic))
+			p.replace(item, flow, func() { item.Value = topic })
+		}
+		kept = append(kept, item)
+	}
+	value.Content = kept
+	return changes
+}
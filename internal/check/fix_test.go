@@ -0,0 +1,184 @@
+package check
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/shankyjs/talks/internal/config"
+	"github.com/shankyjs/talks/internal/talkrepo"
+)
+
+// spellings stands in for the topic registry: k8s is an alias of
+// Kubernetes, and go is spelled Go by most talks.
+var spellings = topicSpellings{"k8s": "Kubernetes", "kubernetes": "Kubernetes", "go": "Go"}
+
+// The wanted documents are complete, so every line a fix does not touch
+// must come back byte for byte.
+func TestFixMetadata(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		src     string
+		want    string
+		changes []string
+	}{
+		{
+			name: "quote date keeps folded description and blank lines",
+			src: `# Talk Metadata
+
+title: "Tracing Go"
+date: 2025-11-19
+
+description: >
+  Folded text that
+  spans lines.
+topics: [Go]   # canonical
+`,
+			want: `# Talk Metadata
+
+title: "Tracing Go"
+date: "2025-11-19"
+
+description: >
+  Folded text that
+  spans lines.
+topics: [Go]   # canonical
+`,
+			changes: []string{"date: quote value"},
+		},
+		{
+			name:    "reformat date keeps comment",
+			src:     "date: 2025/11/19   # the day\nevent: KubeCon\n",
+			want:    "date: \"2025-11-19\"   # the day\nevent: KubeCon\n",
+			changes: []string{`date: reformat "2025/11/19" as "2025-11-19"`, "date: quote value"},
+		},
+		{
+			name:    "reformat quoted date",
+			src:     "date: 'Nov 19, 2025'\n",
+			want:    "date: \"2025-11-19\"\n",
+			changes: []string{`date: reformat "Nov 19, 2025" as "2025-11-19"`, "date: quote value"},
+		},
+		{
+			name: "literal block is not trimmed",
+			src:  "date: \"2025-11-19\"\ndescription: |\n  Line one\n  Line two\n",
+			want: "date: \"2025-11-19\"\ndescription: |\n  Line one\n  Line two\n",
+		},
+		{
+			name:    "trim quoted values",
+			src:     "title: \"  Tracing Go  \" # keep\nevent: ' KubeCon'\n",
+			want:    "title: \"Tracing Go\" # keep\nevent: 'KubeCon'\n",
+			changes: []string{"title: trim whitespace", "event: trim whitespace"},
+		},
+		{
+			name: "trim localized mapping",
+			src: `title:
+  en: "Bar  "
+  es: Barra
+description: {en: " Text", es: "Texto"}
+`,
+			want: `title:
+  en: "Bar"
+  es: Barra
+description: {en: "Text", es: "Texto"}
+`,
+			changes: []string{"title.en: trim whitespace", "description.en: trim whitespace"},
+		},
+		{
+			name: "block topics",
+			src: `topics:
+  - k8s
+  # languages
+  - Go
+  - go   # again
+
+event: KubeCon
+`,
+			want: `topics:
+  - Kubernetes
+  # languages
+  - Go
+
+event: KubeCon
+`,
+			changes: []string{`topics: rename "k8s" to "Kubernetes"`, `topics: remove duplicate "go"`},
+		},
+		{
+			name:    "flow topics",
+			src:     "topics: [\"Go\", go, k8s]  # tags\n",
+			want:    "topics: [\"Go\", Kubernetes]  # tags\n",
+			changes: []string{`topics: remove duplicate "go"`, `topics: rename "k8s" to "Kubernetes"`},
+		},
+		{
+			name:    "flow topics across lines",
+			src:     "topics: [Go,\n  go]\nevent: KubeCon\n",
+			want:    "topics: [Go]\nevent: KubeCon\n",
+			changes: []string{`topics: remove duplicate "go"`},
+		},
+		{
+			name: "nothing to fix",
+			src:  "# comment\ntitle: Go\n\ndate: \"2025-11-19\"\ntopics:\n  - Go\n",
+			want: "# comment\ntitle: Go\n\ndate: \"2025-11-19\"\ntopics:\n  - Go\n",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			got, changes, err := fixMetadata([]byte(tt.src), spellings)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("fixed document\n--- got:\n%s\n--- want:\n%s", got, tt.want)
+			}
+			if !reflect.DeepEqual(changes, tt.changes) {
+				t.Errorf("changes = %q, want %q", changes, tt.changes)
+			}
+		})
+	}
+}
+
+func TestFixMetadataNotInPlace(t *testing.T) {
+	for _, src := range []string{
+		"date: !!str 2025/11/19\n",
+		"topics:\n  - Go\n  - &lang go\n",
+	} {
+		_, _, err := fixMetadata([]byte(src), spellings)
+		if !errors.Is(err, errNotInPlace) {
+			t.Errorf("fixMetadata(%q) error = %v, want errNotInPlace", src, err)
+		}
+	}
+}
+
+func TestMissingReadme(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "2025", "nov-19th-tracing-go"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	lang := config.Language{Code: "en", TalkReadme: "README.md"}
+	talk := talkrepo.Talk{Path: "2025/nov-19th-tracing-go"}
+	talk.Date = "2025-11-19"
+
+	if _, ok, err := missingReadme(root, talk, lang); err != nil || ok {
+		t.Errorf("without a title: ok = %v, err = %v; want no fix", ok, err)
+	}
+
+	talk.Title = talkrepo.Localized{ByLang: map[string]string{"en": "Tracing Go"}}
+	fix, ok, err := missingReadme(root, talk, lang)
+	if err != nil || !ok {
+		t.Fatalf("ok = %v, err = %v; want a fix", ok, err)
+	}
+	if fix.File != "2025/nov-19th-tracing-go/README.md" || fix.Old != nil {
+		t.Errorf("fix of %s (old %q), want a new 2025/nov-19th-tracing-go/README.md", fix.File, fix.Old)
+	}
+	if !strings.HasPrefix(string(fix.New), "# Tracing Go\n") {
+		t.Errorf("README starts with %q, want the title heading", strings.SplitN(string(fix.New), "\n", 2)[0])
+	}
+
+	if err := os.WriteFile(filepath.Join(root, fix.File), fix.New, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok, _ := missingReadme(root, talk, lang); ok {
+		t.Error("fix for a README that exists")
+	}
+}
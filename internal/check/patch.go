@@ -0,0 +1,228 @@
+package check
+
+import (
+	"bytes"
+	"errors"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+)
+
+// errNotInPlace is returned when a metadata fix cannot be written by
+// editing the source text of the changed values alone.
+var errNotInPlace = errors.New("cannot edit in place")
+
+// patch collects edits to the source text of a YAML document, so fixes
+// change the bytes of the values they fix and nothing else: comments,
+// blank lines, quoting and block scalars elsewhere stay as written. The
+// first edit that cannot be made is kept in err and fails apply.
+type patch struct {
+	src   []byte
+	lines []int // offset of the start of each line
+	edits []edit
+	err   error
+}
+
+// edit replaces src[start:end] with text.
+type edit struct {
+	start, end int
+	text       string
+}
+
+func newPatch(src []byte) *patch {
+	p := &patch{src: src, lines: []int{0}}
+	for i, c := range src {
+		if c == '\n' {
+			p.lines = append(p.lines, i+1)
+		}
+	}
+	return p
+}
+
+// offset returns the byte offset of the position of n. yaml.v3 counts
+// columns in characters.
+func (p *patch) offset(n *yaml.Node) (int, error) {
+	if n.Line < 1 || n.Line > len(p.lines) {
+		return 0, errNotInPlace
+	}
+	i := p.lines[n.Line-1]
+	for col := 1; col < n.Column; col++ {
+		if i >= len(p.src) || p.src[i] == '\n' {
+			return 0, errNotInPlace
+		}
+		_, size := utf8.DecodeRune(p.src[i:])
+		i += size
+	}
+	return i, nil
+}
+
+// span returns the source range of the single-line or quoted scalar n.
+// flow is true inside a flow collection, where plain scalars also end at
+// commas and brackets.
+func (p *patch) span(n *yaml.Node, flow bool) (int, int, error) {
+	if n.Kind != yaml.ScalarNode || n.Style&(yaml.TaggedStyle|yaml.LiteralStyle|yaml.FoldedStyle) != 0 {
+		return 0, 0, errNotInPlace
+	}
+	start, err := p.offset(n)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	switch {
+	case n.Style&yaml.DoubleQuotedStyle != 0:
+		for i := start + 1; i < len(p.src); i++ {
+			switch p.src[i] {
+			case '\\':
+				i++
+			case '"':
+				return start, i + 1, nil
+			}
+		}
+	case n.Style&yaml.SingleQuotedStyle != 0:
+		for i := start + 1; i < len(p.src); i++ {
+			if p.src[i] != '\'' {
+				continue
+			}
+			if i+1 < len(p.src) && p.src[i+1] == '\'' {
+				i++
+				continue
+			}
+			return start, i + 1, nil
+		}
+	default:
+		end := start
+		for end < len(p.src) && p.src[end] != '\n' {
+			c := p.src[end]
+			if c == '#' && end > start && (p.src[end-1] == ' ' || p.src[end-1] == '\t') {
+				break
+			}
+			if flow && (c == ',' || c == ']' || c == '}') {
+				break
+			}
+			end++
+		}
+		for end > start && (p.src[end-1] == ' ' || p.src[end-1] == '\t' || p.src[end-1] == '\r') {
+			end--
+		}
+		// A plain scalar continued on the next lines is not handled
+		if string(p.src[start:end]) == n.Value {
+			return start, end, nil
+		}
+	}
+	return 0, 0, errNotInPlace
+}
+
+// replace changes the scalar n with set and rewrites it in its source
+// range.
+func (p *patch) replace(n *yaml.Node, flow bool, set func()) {
+	start, end, err := p.span(n, flow)
+	set()
+	if err != nil {
+		p.fail(err)
+		return
+	}
+	// Without the comments, which stay in the source
+	out, err := yaml.Marshal(&yaml.Node{Kind: yaml.ScalarNode, Style: n.Style, Tag: n.Tag, Value: n.Value})
+	if err != nil {
+		p.fail(err)
+		return
+	}
+	text := strings.TrimSuffix(string(out), "\n")
+	if strings.Contains(text, "\n") {
+		p.fail(errNotInPlace)
+		return
+	}
+	p.edits = append(p.edits, edit{start, end, text})
+}
+
+func (p *patch) fail(err error) {
+	if p.err == nil {
+		p.err = err
+	}
+}
+
+// remove deletes the item of the sequence seq: its line in a block
+// sequence, or the item with the comma before it in a flow sequence.
+func (p *patch) remove(seq, item *yaml.Node) {
+	flow := seq.Style&yaml.FlowStyle != 0
+	start, end, err := p.span(item, flow)
+	if err != nil {
+		p.fail(err)
+		return
+	}
+
+	if flow {
+		i := start
+		for i > 0 && isSpace(p.src[i-1]) {
+			i--
+		}
+		if i == 0 || p.src[i-1] != ',' {
+			p.fail(errNotInPlace)
+			return
+		}
+		p.edits = append(p.edits, edit{i - 1, end, ""})
+		return
+	}
+
+	// The item must be alone on its line: "  - value  # comment"
+	line := p.lines[item.Line-1]
+	prefix := bytes.TrimLeft(p.src[line:start], " ")
+	if len(prefix) == 0 || prefix[0] != '-' || len(bytes.TrimLeft(prefix[1:], " ")) != 0 {
+		p.fail(errNotInPlace)
+		return
+	}
+	next := len(p.src)
+	if item.Line < len(p.lines) {
+		next = p.lines[item.Line]
+	}
+	if rest := bytes.TrimSpace(p.src[end:next]); len(rest) > 0 && rest[0] != '#' {
+		p.fail(errNotInPlace)
+		return
+	}
+	p.edits = append(p.edits, edit{line, next, ""})
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
+
+// apply returns the source with the edits made.
+func (p *patch) apply() ([]byte, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+	sort.Slice(p.edits, func(i, j int) bool { return p.edits[i].start < p.edits[j].start })
+
+	var out bytes.Buffer
+	last := 0
+	for _, e := range p.edits {
+		if e.start < last {
+			return nil, errNotInPlace
+		}
+		out.Write(p.src[last:e.start])
+		out.WriteString(e.text)
+		last = e.end
+	}
+	out.Write(p.src[last:])
+	return out.Bytes(), nil
+}
+
+// sameValues reports whether a and b are the same YAML values: the same
+// kinds, scalar values and resolved tags, and the same mapping and sequence
+// entries in the same order.
+func sameValues(a, b *yaml.Node) bool {
+	if a.Kind != b.Kind || len(a.Content) != len(b.Content) {
+		return false
+	}
+	if a.Kind == yaml.ScalarNode && (a.Value != b.Value || a.ShortTag() != b.ShortTag()) {
+		return false
+	}
+	for i := range a.Content {
+		if !sameValues(a.Content[i], b.Content[i]) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,145 @@
+package check
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/shankyjs/talks/internal/schema"
+)
+
+// WriteJSON writes the findings as a JSON document.
+func (r Result) WriteJSON(w io.Writer) error {
+	findings := r.Findings
+	if findings == nil {
+		findings = []Finding{}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(struct {
+		Errors   int       `json:"errors"`
+		Warnings int       `json:"warnings"`
+		Findings []Finding `json:"findings"`
+	}{len(r.Errors()), len(r.Warnings()), findings})
+}
+
+// WriteGitHub writes the findings as GitHub Actions workflow commands, which
+// show up as annotations on the pull request diff.
+func (r Result) WriteGitHub(w io.Writer) error {
+	for _, f := range r.Findings {
+		props := []string{"file=" + escapeProperty(f.File)}
+		if f.Line > 0 {
+			props = append(props, fmt.Sprintf("line=%d", f.Line))
+		}
+		if f.Column > 0 {
+			props = append(props, fmt.Sprintf("col=%d", f.Column))
+		}
+		props = append(props, "title="+escapeProperty(f.Rule))
+
+		if _, err := fmt.Fprintf(w, "::%s %s::%s\n", f.Severity, strings.Join(props, ","), escapeData(f.Message)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func escapeData(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+func escapeProperty(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(s)
+}
+
+// WriteSARIF writes the findings as a SARIF 2.1.0 log, for code scanning
+// and other dashboards.
+func (r Result) WriteSARIF(w io.Writer) error {
+	type message struct {
+		Text string `json:"text"`
+	}
+	type region struct {
+		StartLine   int `json:"startLine,omitempty"`
+		StartColumn int `json:"startColumn,omitempty"`
+	}
+	type artifactLocation struct {
+		URI string `json:"uri"`
+	}
+	type physicalLocation struct {
+		ArtifactLocation artifactLocation `json:"artifactLocation"`
+		Region           *region          `json:"region,omitempty"`
+	}
+	type location struct {
+		PhysicalLocation physicalLocation `json:"physicalLocation"`
+	}
+	type result struct {
+		RuleID    string     `json:"ruleId"`
+		Level     string     `json:"level"`
+		Message   message    `json:"message"`
+		Locations []location `json:"locations"`
+	}
+	type rule struct {
+		ID               string  `json:"id"`
+		ShortDescription message `json:"shortDescription"`
+	}
+	type driver struct {
+		Name           string `json:"name"`
+		Version        string `json:"version"`
+		InformationURI string `json:"informationUri"`
+		Rules          []rule `json:"rules"`
+	}
+	type tool struct {
+		Driver driver `json:"driver"`
+	}
+	type run struct {
+		Tool    tool     `json:"tool"`
+		Results []result `json:"results"`
+	}
+
+	ids := make([]string, 0, len(Rules))
+	for id := range Rules {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	rules := make([]rule, 0, len(ids))
+	for _, id := range ids {
+		rules = append(rules, rule{ID: id, ShortDescription: message{Rules[id]}})
+	}
+
+	results := make([]result, 0, len(r.Findings))
+	for _, f := range r.Findings {
+		loc := physicalLocation{ArtifactLocation: artifactLocation{URI: f.File}}
+		if f.Line > 0 {
+			loc.Region = &region{StartLine: f.Line, StartColumn: f.Column}
+		}
+		results = append(results, result{
+			RuleID:    f.Rule,
+			Level:     string(f.Severity),
+			Message:   message{f.Message},
+			Locations: []location{{loc}},
+		})
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(struct {
+		Schema  string `json:"$schema"`
+		Version string `json:"version"`
+		Runs    []run  `json:"runs"`
+	}{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs: []run{{
+			Tool: tool{driver{
+				Name:           "talks-check",
+				Version:        schema.Version,
+				InformationURI: "https://github.com/shankyjs/talks",
+				Rules:          rules,
+			}},
+			Results: results,
+		}},
+	})
+}
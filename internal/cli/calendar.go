@@ -0,0 +1,80 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/shankyjs/talks/internal/config"
+	"github.com/shankyjs/talks/internal/ical"
+	"github.com/shankyjs/talks/internal/talkrepo"
+)
+
+func runCalendar(e *env, args []string) int {
+	fs := e.flagSet("calendar", "", "Write the upcoming and past talks as an iCalendar file (talks.ics, in feeds/ by default).")
+	output := fs.String("output", "", "directory to write the calendar to (default: outputs.feeds from talks.yaml)")
+	split := fs.Bool("split", false, "write one calendar per language (talks.es.ics, ...) instead of one in the default or --lang language")
+	asOfFlag := fs.String("as-of", "", "date (YYYY-MM-DD) stamped on the events (default: $SOURCE_DATE_EPOCH, else the latest commit date)")
+	if code, ok := e.parse(fs, args); !ok {
+		return code
+	}
+	if !e.checkFormat("text") {
+		return ExitUsage
+	}
+	asOf, code, ok := e.asOf(*asOfFlag, false)
+	if !ok {
+		return code
+	}
+	if *output == "" {
+		*output = e.cfg.Outputs.Feeds
+	}
+
+	talks, _, err := talkrepo.Load(e.Root)
+	if err != nil {
+		e.errorf("%v", err)
+		return ExitFailure
+	}
+
+	dir := *output
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(e.Root, dir)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		e.errorf("%v", err)
+		return ExitFailure
+	}
+
+	langs := []config.Language{e.cfg.Languages[0]}
+	if *split {
+		langs = e.languages()
+	} else if lang, ok := e.cfg.Language(e.Lang); ok {
+		langs = []config.Language{lang}
+	}
+
+	for i, lang := range langs {
+		suffix := ""
+		if *split && lang.Code != e.cfg.Languages[0].Code {
+			suffix = "." + lang.Code
+		}
+
+		c, skipped, err := ical.New(e.Root, e.cfg, talks, lang.Code, asOf)
+		if err != nil {
+			e.errorf("%v", err)
+			return ExitFailure
+		}
+		// Every language skips the same talks
+		if i == 0 {
+			for _, err := range skipped {
+				e.warnf("%v (left out of the calendar)", err)
+			}
+		}
+
+		name := ical.FileName(suffix)
+		if err := os.WriteFile(filepath.Join(dir, name), ical.Encode(c), 0644); err != nil {
+			e.errorf("writing %s: %v", name, err)
+			return ExitFailure
+		}
+		e.logf("✅ Wrote %s (%d talks)\n", filepath.ToSlash(filepath.Join(*output, name)), len(c.Events))
+	}
+
+	return ExitOK
+}
@@ -0,0 +1,121 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/shankyjs/talks/internal/check"
+)
+
+func runCheck(e *env, args []string) int {
+	fs := e.flagSet("check", "", "Validate that every talk directory has valid metadata and READMEs.\n\nFormats: text (default), json, sarif, github (workflow annotations).")
+	fix := fs.Bool("fix", false, "fix missing READMEs, date formatting, duplicate or inconsistently cased topics and whitespace before checking")
+	dryRun := fs.Bool("dry-run", false, "with --fix, print the fixes as a unified diff instead of writing them")
+	asOfFlag := fs.String("as-of", "", "date (YYYY-MM-DD) splitting past talks, whose placeholders are errors, from upcoming ones (default: $SOURCE_DATE_EPOCH, else the latest commit date)")
+	if code, ok := e.parse(fs, args); !ok {
+		return code
+	}
+	if !e.checkFormat("text", "json", "sarif", "github") {
+		return ExitUsage
+	}
+	if *dryRun && !*fix {
+		e.errorf("--dry-run requires --fix")
+		return ExitUsage
+	}
+
+	if *fix {
+		if code, done := runFix(e, *dryRun); done {
+			return code
+		}
+	}
+
+	asOf, code, ok := e.asOf(*asOfFlag, e.Format != "text")
+	if !ok {
+		return code
+	}
+
+	result, err := check.Run(e.Root, e.cfg, asOf)
+	if err != nil {
+		e.errorf("%v", err)
+		return ExitFailure
+	}
+
+	switch e.Format {
+	case "json":
+		err = result.WriteJSON(e.stdout)
+	case "sarif":
+		err = result.WriteSARIF(e.stdout)
+	case "github":
+		err = result.WriteGitHub(e.stdout)
+	default:
+		printCheckText(e, result)
+	}
+	if err != nil {
+		e.errorf("%v", err)
+		return ExitFailure
+	}
+
+	if !result.OK() {
+		return ExitFailure
+	}
+	return ExitOK
+}
+
+func printCheckText(e *env, result check.Result) {
+	warnings, errs := result.Warnings(), result.Errors()
+
+	// Print results
+	if len(warnings) > 0 && !e.Quiet {
+		fmt.Fprintln(e.stdout, "\n⚠️  Warnings:")
+		for _, w := range warnings {
+			fmt.Fprintf(e.stdout, "  ⚠️  %s\n", w)
+		}
+	}
+
+	if len(errs) > 0 {
+		fmt.Fprintln(e.stdout, "\n❌ Errors:")
+		for _, msg := range errs {
+			fmt.Fprintf(e.stdout, "  ❌ %s\n", msg)
+		}
+		fmt.Fprintln(e.stdout, "\nPlease fix the errors above.")
+		return
+	}
+
+	if len(warnings) == 0 {
+		e.logf("✅ All talk directories have valid metadata!\n")
+	}
+}
+
+// runFix computes and applies (or, for a dry run, prints) the fixes. It
+// returns done when the command should stop there.
+func runFix(e *env, dryRun bool) (code int, done bool) {
+	fixes, err := check.Fixes(e.Root, e.cfg)
+	if err != nil {
+		e.errorf("%v", err)
+		return ExitFailure, true
+	}
+
+	if dryRun {
+		for _, fix := range fixes {
+			fmt.Fprint(e.stdout, fix.Diff())
+		}
+		return ExitOK, true
+	}
+
+	if err := check.Apply(e.Root, fixes); err != nil {
+		e.errorf("%v", err)
+		return ExitFailure, true
+	}
+
+	// Keep machine-readable output on stdout clean
+	log := e.stdout
+	if e.Format != "text" {
+		log = e.stderr
+	}
+	if !e.Quiet {
+		for _, fix := range fixes {
+			fmt.Fprintf(log, "🔧 Fixed %s: %s\n", fix.File, strings.Join(fix.Changes, ", "))
+		}
+	}
+	return ExitOK, false
+}
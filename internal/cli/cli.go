@@ -0,0 +1,268 @@
+// Package cli implements the talks command and its subcommands.
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	_ "time/tzdata" // time zones of talk times, on systems without a zoneinfo database
+
+	"github.com/shankyjs/talks/internal/asof"
+	"github.com/shankyjs/talks/internal/config"
+	"github.com/shankyjs/talks/internal/history"
+	"github.com/shankyjs/talks/internal/talkrepo"
+)
+
+// Exit codes returned by Run.
+const (
+	ExitOK      = 0 // the command succeeded
+	ExitFailure = 1 // the command failed or found problems
+	ExitUsage   = 2 // invalid flags or arguments
+)
+
+// Options are the global flags shared by every subcommand.
+type Options struct {
+	Root   string
+	Lang   string
+	Format string
+	Quiet  bool
+}
+
+func (o *Options) register(fs *flag.FlagSet) {
+	fs.StringVar(&o.Root, "root", o.Root, "repository root directory (default: detected from the working directory)")
+	fs.StringVar(&o.Lang, "lang", o.Lang, "limit output to one configured language (e.g. en, es)")
+	fs.StringVar(&o.Format, "format", o.Format, "output format")
+	fs.BoolVar(&o.Quiet, "quiet", o.Quiet, "only print results and errors")
+}
+
+type command struct {
+	name    string
+	summary string
+	run     func(e *env, args []string) int
+}
+
+var commands = []command{
+	{"new", "Create a new talk directory from the templates", runNew},
+	{"index", "Regenerate the talks index in the READMEs", runIndex},
+	{"check", "Validate talk directories and metadata files", runCheck},
+	{"stats", "Generate the talk statistics report", runStats},
+	{"list", "List all talks", runList},
+	{"show", "Show the metadata of a single talk", runShow},
+	{"schema", "Print the JSON Schema for metadata.yaml", runSchema},
+	{"site", "Build the talks catalog as a static HTML site", runSite},
+	{"feed", "Write the Atom and JSON feeds of talks", runFeed},
+	{"calendar", "Write the iCalendar file of talks", runCalendar},
+	{"export", "Export the talks catalog as JSON, YAML or CSV", runExport},
+}
+
+func lookup(name string) (command, bool) {
+	for _, cmd := range commands {
+		if cmd.name == name {
+			return cmd, true
+		}
+	}
+	return command{}, false
+}
+
+// env carries the global options, the repository configuration and the
+// output streams to a subcommand.
+type env struct {
+	Options
+	cfg    *config.Config
+	stdout io.Writer
+	stderr io.Writer
+}
+
+// logf prints progress messages, which --quiet suppresses.
+func (e *env) logf(format string, args ...any) {
+	if !e.Quiet {
+		fmt.Fprintf(e.stdout, format, args...)
+	}
+}
+
+// warnf prints a warning to stderr.
+func (e *env) warnf(format string, args ...any) {
+	fmt.Fprintf(e.stderr, "⚠️  Warning: "+format+"\n", args...)
+}
+
+// errorf prints an error message to stderr.
+func (e *env) errorf(format string, args ...any) {
+	fmt.Fprintf(e.stderr, "❌ Error: "+format+"\n", args...)
+}
+
+// history returns the git history of talks, warning when it is not
+// available in full.
+func (e *env) history(talks []talkrepo.Talk) map[string]history.Talk {
+	hist, err := history.Load(e.Root, talks)
+	if err != nil {
+		e.warnf("%v", err)
+	}
+	return hist
+}
+
+// flagSet returns a flag set for a subcommand with the global flags
+// registered, so they can be given before or after the subcommand name.
+func (e *env) flagSet(name, synopsis, summary string) *flag.FlagSet {
+	fs := flag.NewFlagSet("talks "+name, flag.ContinueOnError)
+	fs.SetOutput(e.stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(e.stderr, "%s\n\nUsage: talks %s [flags] %s\n\nFlags:\n", summary, name, synopsis)
+		fs.PrintDefaults()
+	}
+	e.register(fs)
+	return fs
+}
+
+// parse parses args into fs, resolves the repository root and loads its
+// configuration. When it returns false the caller should exit with the
+// returned code.
+func (e *env) parse(fs *flag.FlagSet, args []string) (int, bool) {
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return ExitOK, false
+		}
+		return ExitUsage, false
+	}
+
+	if e.Root == "" {
+		root, err := talkrepo.FindRoot(".")
+		if err != nil {
+			e.errorf("%v (use --root)", err)
+			return ExitFailure, false
+		}
+		e.Root = root
+	}
+
+	cfg, err := config.Load(e.Root)
+	if err != nil {
+		e.errorf("%v", err)
+		return ExitFailure, false
+	}
+	e.cfg = cfg
+
+	if e.Lang != "" {
+		if _, ok := cfg.Language(e.Lang); !ok {
+			e.errorf("unknown language %q", e.Lang)
+			return ExitUsage, false
+		}
+	}
+	return ExitOK, true
+}
+
+// asOf resolves the date past and upcoming talks are split at from the
+// --as-of flag value, SOURCE_DATE_EPOCH or the latest commit. data reports
+// whether the command prints data (JSON, CSV, ...) to stdout, in which case
+// the date is logged to stderr instead. On failure it returns the exit
+// code: ExitUsage for an invalid --as-of value, ExitFailure otherwise.
+func (e *env) asOf(value string, data bool) (string, int, bool) {
+	date, source, err := asof.Resolve(e.Root, value)
+	if err != nil {
+		e.errorf("%v", err)
+		// Only the flag is parsed when it is given
+		if value != "" {
+			return "", ExitUsage, false
+		}
+		return "", ExitFailure, false
+	}
+	if !data {
+		e.logf("📅 As of %s (%s)\n", date, source)
+	} else if !e.Quiet {
+		fmt.Fprintf(e.stderr, "📅 As of %s (%s)\n", date, source)
+	}
+	return date, ExitOK, true
+}
+
+// checkFormat reports a usage error unless --format is one of formats.
+// The first format is the default.
+func (e *env) checkFormat(formats ...string) bool {
+	if e.Format == "" {
+		e.Format = formats[0]
+	}
+	for _, f := range formats {
+		if e.Format == f {
+			return true
+		}
+	}
+	e.errorf("unsupported format %q (supported: %s)", e.Format, strings.Join(formats, ", "))
+	return false
+}
+
+// Run executes the talks command with the given arguments (without the
+// program name) and returns the process exit code.
+func Run(args []string, stdout, stderr io.Writer) int {
+	e := &env{stdout: stdout, stderr: stderr}
+
+	fs := flag.NewFlagSet("talks", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() { usage(stderr, fs) }
+	e.register(fs)
+
+	// The root is resolved by the subcommand, once all flags are known.
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return ExitOK
+		}
+		return ExitUsage
+	}
+
+	if fs.NArg() == 0 {
+		usage(stderr, fs)
+		return ExitUsage
+	}
+
+	name, rest := fs.Arg(0), fs.Args()[1:]
+	if name == "help" {
+		return runHelp(e, fs, rest)
+	}
+
+	cmd, ok := lookup(name)
+	if !ok {
+		e.errorf("unknown command %q", name)
+		usage(stderr, fs)
+		return ExitUsage
+	}
+
+	return cmd.run(e, rest)
+}
+
+func runHelp(e *env, fs *flag.FlagSet, args []string) int {
+	if len(args) == 0 {
+		usage(e.stdout, fs)
+		return ExitOK
+	}
+
+	cmd, ok := lookup(args[0])
+	if !ok {
+		e.errorf("unknown command %q", args[0])
+		return ExitUsage
+	}
+	return cmd.run(e, []string{"-h"})
+}
+
+func usage(w io.Writer, fs *flag.FlagSet) {
+	fmt.Fprintln(w, "📚 talks - manage the talks repository")
+	fmt.Fprintln(w, "")
+	fmt.Fprintln(w, "Usage: talks [global flags] <command> [flags] [args]")
+	fmt.Fprintln(w, "")
+	fmt.Fprintln(w, "Commands:")
+
+	names := make([]string, 0, len(commands))
+	for _, cmd := range commands {
+		names = append(names, cmd.name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		cmd, _ := lookup(name)
+		fmt.Fprintf(w, "  %-8s %s\n", cmd.name, cmd.summary)
+	}
+
+	fmt.Fprintln(w, "")
+	fmt.Fprintln(w, "Global flags:")
+	fs.SetOutput(w)
+	fs.PrintDefaults()
+	fmt.Fprintln(w, "")
+	fmt.Fprintln(w, "Run 'talks help <command>' for details on a command.")
+}
@@ -0,0 +1,67 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/shankyjs/talks/internal/export"
+	"github.com/shankyjs/talks/internal/talkrepo"
+)
+
+func runExport(e *env, args []string) int {
+	fs := e.flagSet("export", "", "Print the whole talks catalog (normalized metadata plus status, READMEs and links) as JSON, YAML or CSV (--format json|yaml|csv, default json).")
+	output := fs.String("output", "", "write the catalog to this file (relative to the root) instead of stdout; its extension sets the format when --format is not given")
+	asOfFlag := fs.String("as-of", "", "date (YYYY-MM-DD) splitting past and upcoming talks (default: $SOURCE_DATE_EPOCH, else the latest commit date)")
+	if code, ok := e.parse(fs, args); !ok {
+		return code
+	}
+	if e.Format == "" && *output != "" {
+		switch ext := strings.TrimPrefix(filepath.Ext(*output), "."); ext {
+		case "yml":
+			e.Format = "yaml"
+		case "json", "yaml", "csv":
+			e.Format = ext
+		}
+	}
+	if !e.checkFormat(export.Formats...) {
+		return ExitUsage
+	}
+	asOf, code, ok := e.asOf(*asOfFlag, *output == "")
+	if !ok {
+		return code
+	}
+
+	talks, _, err := talkrepo.Load(e.Root)
+	if err != nil {
+		e.errorf("%v", err)
+		return ExitFailure
+	}
+
+	c := export.New(e.Root, e.cfg, talks, asOf, e.history(talks))
+	data, err := export.Encode(c, e.Format)
+	if err != nil {
+		e.errorf("%v", err)
+		return ExitFailure
+	}
+
+	if *output == "" {
+		e.stdout.Write(data)
+		return ExitOK
+	}
+
+	path := *output
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(e.Root, path)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		e.errorf("%v", err)
+		return ExitFailure
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		e.errorf("writing %s: %v", *output, err)
+		return ExitFailure
+	}
+	e.logf("✅ Wrote %s (%d talks)\n", *output, len(c.Talks))
+	return ExitOK
+}
@@ -0,0 +1,84 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/shankyjs/talks/internal/config"
+	"github.com/shankyjs/talks/internal/feed"
+	"github.com/shankyjs/talks/internal/talkrepo"
+)
+
+func runFeed(e *env, args []string) int {
+	fs := e.flagSet("feed", "", "Write the talks as Atom (feed.xml) and JSON Feed (feed.json) files (in feeds/ by default).")
+	output := fs.String("output", "", "directory to write the feeds to (default: outputs.feeds from talks.yaml)")
+	split := fs.Bool("split", false, "write one pair of feeds per language (feed.es.xml, ...) instead of one in the default or --lang language")
+	asOfFlag := fs.String("as-of", "", "latest date (YYYY-MM-DD) the feeds can be updated on (default: $SOURCE_DATE_EPOCH, else the latest commit date)")
+	if code, ok := e.parse(fs, args); !ok {
+		return code
+	}
+	if !e.checkFormat("text") {
+		return ExitUsage
+	}
+	asOf, code, ok := e.asOf(*asOfFlag, false)
+	if !ok {
+		return code
+	}
+	if *output == "" {
+		*output = e.cfg.Outputs.Feeds
+	}
+
+	talks, _, err := talkrepo.Load(e.Root)
+	if err != nil {
+		e.errorf("%v", err)
+		return ExitFailure
+	}
+
+	dir := *output
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(e.Root, dir)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		e.errorf("%v", err)
+		return ExitFailure
+	}
+
+	langs := []config.Language{e.cfg.Languages[0]}
+	if *split {
+		langs = e.languages()
+	} else if lang, ok := e.cfg.Language(e.Lang); ok {
+		langs = []config.Language{lang}
+	}
+
+	for _, lang := range langs {
+		suffix := ""
+		if *split && lang.Code != e.cfg.Languages[0].Code {
+			suffix = "." + lang.Code
+		}
+
+		f, err := feed.New(e.Root, e.cfg, talks, lang.Code, suffix, asOf)
+		if err != nil {
+			e.errorf("%v", err)
+			return ExitFailure
+		}
+
+		atomFile, jsonFile := feed.FileNames(suffix)
+		for _, out := range []struct {
+			name   string
+			render func(feed.Feed) ([]byte, error)
+		}{{atomFile, feed.Atom}, {jsonFile, feed.JSON}} {
+			content, err := out.render(f)
+			if err != nil {
+				e.errorf("rendering %s: %v", out.name, err)
+				return ExitFailure
+			}
+			if err := os.WriteFile(filepath.Join(dir, out.name), content, 0644); err != nil {
+				e.errorf("writing %s: %v", out.name, err)
+				return ExitFailure
+			}
+			e.logf("✅ Wrote %s (%d talks)\n", filepath.ToSlash(filepath.Join(*output, out.name)), len(f.Entries))
+		}
+	}
+
+	return ExitOK
+}
@@ -0,0 +1,93 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/shankyjs/talks/internal/config"
+	"github.com/shankyjs/talks/internal/diff"
+	"github.com/shankyjs/talks/internal/index"
+	"github.com/shankyjs/talks/internal/talkrepo"
+)
+
+func runIndex(e *env, args []string) int {
+	fs := e.flagSet("index", "", "Regenerate the statistics and talks index sections of the READMEs.")
+	checkOnly := fs.Bool("check", false, "report READMEs that are out of date as a unified diff instead of writing them; exits 1 when any is")
+	asOfFlag := fs.String("as-of", "", "date (YYYY-MM-DD) splitting past and upcoming talks (default: $SOURCE_DATE_EPOCH, else the latest commit date)")
+	if code, ok := e.parse(fs, args); !ok {
+		return code
+	}
+	if !e.checkFormat("text") {
+		return ExitUsage
+	}
+	asOf, code, ok := e.asOf(*asOfFlag, false)
+	if !ok {
+		return code
+	}
+
+	e.logf("🔍 Scanning for talks...\n")
+
+	talks, _, err := talkrepo.Load(e.Root)
+	if err != nil {
+		e.errorf("%v", err)
+		return ExitFailure
+	}
+
+	// Sort talks by date (newest first)
+	talkrepo.SortByDateDesc(talks)
+
+	e.logf("📚 Found %d talks\n", len(talks))
+
+	if *checkOnly {
+		return checkIndex(e, talks, asOf)
+	}
+
+	for _, lang := range e.languages() {
+		if err := index.UpdateReadme(e.Root, e.cfg, lang, talks, asOf); err != nil {
+			e.errorf("updating %s: %v", lang.Readme, err)
+			return ExitFailure
+		}
+		e.logf("✅ Updated %s\n", lang.Readme)
+	}
+
+	e.logf("\n✨ Index generation complete!\n")
+	return ExitOK
+}
+
+// checkIndex renders the READMEs in memory and prints a diff for each one
+// that differs from the file on disk.
+func checkIndex(e *env, talks []talkrepo.Talk, asOf string) int {
+	stale := 0
+	for _, lang := range e.languages() {
+		current, updated, err := index.RenderReadme(e.Root, e.cfg, lang, talks, asOf)
+		if err != nil {
+			e.errorf("rendering %s: %v", lang.Readme, err)
+			return ExitFailure
+		}
+
+		if bytes.Equal(current, updated) {
+			e.logf("✅ %s is up to date\n", lang.Readme)
+			continue
+		}
+
+		stale++
+		e.logf("❌ %s is out of date\n", lang.Readme)
+		fmt.Fprint(e.stdout, diff.Unified("a/"+lang.Readme, "b/"+lang.Readme, string(current), string(updated), 3))
+	}
+
+	if stale > 0 {
+		e.logf("\n💡 Run 'talks index' (or 'make update-index') to update %d README(s)\n", stale)
+		return ExitFailure
+	}
+
+	e.logf("\n✨ Index is in sync with metadata!\n")
+	return ExitOK
+}
+
+// languages returns the configured languages selected by --lang.
+func (e *env) languages() []config.Language {
+	if lang, ok := e.cfg.Language(e.Lang); ok {
+		return []config.Language{lang}
+	}
+	return e.cfg.Languages
+}
@@ -0,0 +1,177 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/shankyjs/talks/internal/talkrepo"
+)
+
+// talkJSON is the JSON representation of a talk used by list and show.
+type talkJSON struct {
+	Path        string              `json:"path"`
+	Year        string              `json:"year"`
+	Title       talkrepo.Localized  `json:"title"`
+	Date        string              `json:"date"`
+	Event       string              `json:"event,omitempty"`
+	Topics      []string            `json:"topics"`
+	Description *talkrepo.Localized `json:"description,omitempty"`
+	SlidesURL   string              `json:"slides_url,omitempty"`
+	VideoURL    string              `json:"video_url,omitempty"`
+	Time        string              `json:"time,omitempty"`
+	Timezone    string              `json:"timezone,omitempty"`
+	Location    string              `json:"location,omitempty"`
+	Materials   map[string][]string `json:"materials,omitempty"` // paths in the talk directory, by kind
+}
+
+func toJSON(talk talkrepo.Talk) talkJSON {
+	var description *talkrepo.Localized
+	if len(talk.Description.Values()) > 0 {
+		description = &talk.Description
+	}
+	var materials map[string][]string
+	for _, m := range talk.Materials {
+		if materials == nil {
+			materials = make(map[string][]string)
+		}
+		materials[m.Kind] = m.Paths
+	}
+	return talkJSON{
+		Path:        talk.Path,
+		Year:        talk.Year,
+		Title:       talk.Title,
+		Date:        talk.Date,
+		Event:       talk.Event,
+		Topics:      talk.Topics,
+		Description: description,
+		SlidesURL:   talk.SlidesURL,
+		VideoURL:    talk.VideoURL,
+		Time:        talk.Time,
+		Timezone:    talk.Timezone,
+		Location:    talk.Location,
+		Materials:   materials,
+	}
+}
+
+func (e *env) printJSON(v any) int {
+	enc := json.NewEncoder(e.stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		e.errorf("%v", err)
+		return ExitFailure
+	}
+	return ExitOK
+}
+
+func runList(e *env, args []string) int {
+	fs := e.flagSet("list", "", "List all talks, newest first.")
+	if code, ok := e.parse(fs, args); !ok {
+		return code
+	}
+	if !e.checkFormat("text", "json") {
+		return ExitUsage
+	}
+
+	talks, _, err := talkrepo.Load(e.Root)
+	if err != nil {
+		e.errorf("%v", err)
+		return ExitFailure
+	}
+	talkrepo.SortByDateDesc(talks)
+
+	if e.Format == "json" {
+		out := make([]talkJSON, 0, len(talks))
+		for _, talk := range talks {
+			out = append(out, toJSON(talk))
+		}
+		return e.printJSON(out)
+	}
+
+	tw := tabwriter.NewWriter(e.stdout, 0, 4, 2, ' ', 0)
+	for _, talk := range talks {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", talk.Date, talk.Path, talk.Title.Text(e.Lang))
+	}
+	tw.Flush()
+	return ExitOK
+}
+
+func runShow(e *env, args []string) int {
+	fs := e.flagSet("show", "<talk>", "Show the metadata of a talk, given its path (2025/oct-30th-...) or directory name.")
+	if code, ok := e.parse(fs, args); !ok {
+		return code
+	}
+	if !e.checkFormat("text", "json") {
+		return ExitUsage
+	}
+
+	if fs.NArg() != 1 {
+		e.errorf("show takes exactly one talk")
+		fs.Usage()
+		return ExitUsage
+	}
+
+	talk, ok := e.findTalk(fs.Arg(0))
+	if !ok {
+		return ExitFailure
+	}
+
+	if e.Format == "json" {
+		return e.printJSON(toJSON(talk))
+	}
+
+	tw := tabwriter.NewWriter(e.stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(tw, "Title:\t%s\n", talk.Title.Text(e.Lang))
+	fmt.Fprintf(tw, "Date:\t%s\n", strings.TrimSpace(strings.Join([]string{talk.Date, talk.Time, talk.Timezone}, " ")))
+	fmt.Fprintf(tw, "Event:\t%s\n", talk.Event)
+	if talk.Location != "" {
+		fmt.Fprintf(tw, "Location:\t%s\n", talk.Location)
+	}
+	fmt.Fprintf(tw, "Topics:\t%s\n", strings.Join(talk.Topics, ", "))
+	fmt.Fprintf(tw, "Description:\t%s\n", talk.Description.Text(e.Lang))
+	if talk.SlidesURL != "" {
+		fmt.Fprintf(tw, "Slides:\t%s\n", talk.SlidesURL)
+	}
+	if talk.VideoURL != "" {
+		fmt.Fprintf(tw, "Video:\t%s\n", talk.VideoURL)
+	}
+	for i, m := range talk.Materials {
+		label := ""
+		if i == 0 {
+			label = "Materials:"
+		}
+		fmt.Fprintf(tw, "%s\t%s: %s\n", label, m.Kind, strings.Join(m.Paths, ", "))
+	}
+	fmt.Fprintf(tw, "Path:\t%s\n", talk.Path)
+	tw.Flush()
+	return ExitOK
+}
+
+// findTalk loads the talk identified by name, which is either its path
+// relative to the root or its directory name.
+func (e *env) findTalk(name string) (talkrepo.Talk, bool) {
+	name = strings.TrimSuffix(strings.TrimPrefix(name, "./"), "/")
+
+	talks, talkErrs, err := talkrepo.Load(e.Root)
+	if err != nil {
+		e.errorf("%v", err)
+		return talkrepo.Talk{}, false
+	}
+
+	for _, talk := range talks {
+		if talk.Path == name || path.Base(talk.Path) == name {
+			return talk, true
+		}
+	}
+	for _, talkErr := range talkErrs {
+		if talkErr.Path == name || path.Base(talkErr.Path) == name {
+			e.errorf("%v", talkErr)
+			return talkrepo.Talk{}, false
+		}
+	}
+
+	e.errorf("talk not found: %s", name)
+	return talkrepo.Talk{}, false
+}
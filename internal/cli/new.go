@@ -0,0 +1,57 @@
+package cli
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/shankyjs/talks/internal/scaffold"
+	"github.com/shankyjs/talks/internal/talkrepo"
+)
+
+func runNew(e *env, args []string) int {
+	fs := e.flagSet("new", "-date YYYY-MM-DD -slug SLUG [-title TITLE]", "Create a new talk directory from the templates.")
+	date := fs.String("date", "", "talk date in YYYY-MM-DD format")
+	slug := fs.String("slug", "", "talk slug for directory name")
+	title := fs.String("title", "", "talk title (optional, auto-generated from slug)")
+	if code, ok := e.parse(fs, args); !ok {
+		return code
+	}
+	if !e.checkFormat("text") {
+		return ExitUsage
+	}
+
+	if *date == "" || *slug == "" {
+		e.errorf("-date and -slug are required")
+		fs.Usage()
+		return ExitUsage
+	}
+
+	talkPath, files, err := scaffold.Create(e.Root, e.cfg, *date, *slug, *title)
+	if err != nil {
+		e.errorf("%v", err)
+		return ExitFailure
+	}
+
+	e.logf("📁 Created new talk directory: %s\n", talkPath)
+	e.logf("✅ Talk directory created successfully!\n")
+	e.logf("\n")
+	e.logf("Next steps:\n")
+	e.logf("  1. Edit %s/%s with your talk details\n", talkPath, talkrepo.MetadataFile)
+	step := 2
+	for _, lang := range e.cfg.Languages {
+		verb := "Write"
+		if slices.Contains(files, lang.TalkReadme) {
+			verb = "Update"
+		}
+		e.logf("  %d. %s %s/%s with your content (%s)\n", step, verb, talkPath, lang.TalkReadme, strings.ToUpper(lang.Code))
+		step++
+	}
+	e.logf("  %d. Run 'make update-index' to regenerate the talks index\n", step)
+	e.logf("\n")
+	e.logf("📝 Files created:\n")
+	for _, file := range files {
+		e.logf("  - %s/%s\n", talkPath, file)
+	}
+
+	return ExitOK
+}
@@ -0,0 +1,48 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/shankyjs/talks/internal/schema"
+)
+
+func runSchema(e *env, args []string) int {
+	fs := e.flagSet("schema", "", "Print the JSON Schema for metadata.yaml (version "+schema.Version+").\n\nEditors can use a saved copy through a '# yaml-language-server: $schema=<path>' header.")
+	output := fs.String("output", "", "write the schema to this file (relative to the root) instead of stdout")
+	if code, ok := e.parse(fs, args); !ok {
+		return code
+	}
+	if !e.checkFormat("json") {
+		return ExitUsage
+	}
+
+	s, err := schema.Metadata(e.cfg)
+	if err != nil {
+		e.errorf("%v", err)
+		return ExitFailure
+	}
+
+	data, err := s.JSON()
+	if err != nil {
+		e.errorf("%v", err)
+		return ExitFailure
+	}
+
+	if *output == "" {
+		e.stdout.Write(data)
+		return ExitOK
+	}
+
+	path := filepath.Join(e.Root, *output)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		e.errorf("%v", err)
+		return ExitFailure
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		e.errorf("writing %s: %v", *output, err)
+		return ExitFailure
+	}
+	e.logf("✅ Wrote %s\n", *output)
+	return ExitOK
+}
@@ -0,0 +1,48 @@
+package cli
+
+import (
+	"github.com/shankyjs/talks/internal/site"
+	"github.com/shankyjs/talks/internal/talkrepo"
+)
+
+func runSite(e *env, args []string) int {
+	fs := e.flagSet("site", "", "Build the talks catalog as a static HTML site (in site/ by default).")
+	output := fs.String("output", "", "directory to write the site to (default: outputs.site from talks.yaml)")
+	asOfFlag := fs.String("as-of", "", "date (YYYY-MM-DD) splitting past and upcoming talks (default: $SOURCE_DATE_EPOCH, else the latest commit date)")
+	if code, ok := e.parse(fs, args); !ok {
+		return code
+	}
+	if !e.checkFormat("text") {
+		return ExitUsage
+	}
+	asOf, code, ok := e.asOf(*asOfFlag, false)
+	if !ok {
+		return code
+	}
+	if *output == "" {
+		*output = e.cfg.Outputs.Site
+	}
+
+	e.logf("🔍 Scanning for talks...\n")
+
+	talks, _, err := talkrepo.Load(e.Root)
+	if err != nil {
+		e.errorf("%v", err)
+		return ExitFailure
+	}
+
+	// Sort talks by date (newest first)
+	talkrepo.SortByDateDesc(talks)
+
+	e.logf("📚 Found %d talks\n", len(talks))
+
+	summary, err := site.Build(e.Root, e.cfg, talks, asOf, *output)
+	if err != nil {
+		e.errorf("building site: %v", err)
+		return ExitFailure
+	}
+
+	e.logf("✅ Wrote %d pages and %d assets to %s\n", summary.Pages, summary.Assets, *output)
+	e.logf("\n✨ Site generation complete!\n")
+	return ExitOK
+}
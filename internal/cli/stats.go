@@ -0,0 +1,72 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/shankyjs/talks/internal/stats"
+	"github.com/shankyjs/talks/internal/talkrepo"
+)
+
+func runStats(e *env, args []string) int {
+	fs := e.flagSet("stats", "", "Print the talk statistics report and save it (to stats.txt by default).\n\n--format markdown (the default) prints the report and saves it; json, csv and svg (bar charts) are printed, or written to --output.")
+	asOfFlag := fs.String("as-of", "", "date (YYYY-MM-DD) splitting past and upcoming talks (default: $SOURCE_DATE_EPOCH, else the latest commit date)")
+	output := fs.String("output", "", "write the report to this file (relative to the root) instead (default: outputs.stats from talks.yaml for markdown, stdout otherwise)")
+	if code, ok := e.parse(fs, args); !ok {
+		return code
+	}
+	if e.Format == "text" {
+		e.Format = "markdown"
+	}
+	if !e.checkFormat(stats.Formats...) {
+		return ExitUsage
+	}
+	asOf, code, ok := e.asOf(*asOfFlag, *output == "" && e.Format != "markdown")
+	if !ok {
+		return code
+	}
+
+	talks, _, err := talkrepo.Load(e.Root)
+	if err != nil {
+		e.errorf("%v", err)
+		return ExitFailure
+	}
+
+	report, err := stats.Render(stats.Compute(talks, e.cfg, asOf, e.history(talks)), e.Format)
+	if err != nil {
+		e.errorf("%v", err)
+		return ExitFailure
+	}
+
+	// The markdown report is printed and saved to stats.txt unless --output
+	// says otherwise; other formats go to one place only
+	file := *output
+	if file == "" {
+		if e.Format != "markdown" {
+			e.stdout.Write(report)
+			return ExitOK
+		}
+		file = e.cfg.Outputs.Stats
+		if !e.Quiet {
+			e.stdout.Write(report)
+		}
+	}
+
+	path := file
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(e.Root, path)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		e.errorf("%v", err)
+		return ExitFailure
+	}
+	if err := os.WriteFile(path, report, 0644); err != nil {
+		e.errorf("writing %s: %v", file, err)
+		return ExitFailure
+	}
+	if *output != "" {
+		e.logf("✅ Wrote %s\n", file)
+	}
+
+	return ExitOK
+}
@@ -0,0 +1,280 @@
+// Package config loads the repository configuration file (talks.yaml).
+//
+// Every setting has a default matching this repository, so the file only
+// needs to list what a fork wants to change.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/shankyjs/talks/internal/talkrepo"
+	"github.com/shankyjs/talks/internal/topics"
+)
+
+// Config is the content of talks.yaml.
+type Config struct {
+	Languages    []Language   `yaml:"languages"`
+	Metadata     Metadata     `yaml:"metadata"`
+	Placeholders Placeholders `yaml:"placeholders"`
+	Outputs      Outputs      `yaml:"outputs"`
+	Index        Index        `yaml:"index"`
+	Stats        Stats        `yaml:"stats"`
+	Taxonomy     string       `yaml:"taxonomy"`   // topic registry file, relative to the root
+	Messages     string       `yaml:"messages"`   // message catalog overrides directory, relative to the root
+	Templates    string       `yaml:"templates"`  // index template overrides directory, relative to the root
+	SourceURL    string       `yaml:"source_url"` // URL repository files are browsable at, e.g. on GitHub
+	SiteURL      string       `yaml:"site_url"`   // URL the site is published at
+	Author       string       `yaml:"author"`     // author of the feeds
+
+	// Topics is the topic registry loaded from Taxonomy.
+	Topics *topics.Registry `yaml:"-"`
+}
+
+// Language is a language the talks are documented and indexed in.
+type Language struct {
+	Code        string `yaml:"code"`         // e.g. "es"
+	Readme      string `yaml:"readme"`       // repository README holding the index, relative to the root
+	TalkReadme  string `yaml:"talk_readme"`  // README file name inside each talk directory
+	IndexMarker string `yaml:"index_marker"` // heading of the index, used to migrate READMEs without marker comments
+	EndMarker   string `yaml:"end_marker"`   // heading that follows the index, used with IndexMarker
+}
+
+// Metadata lists the metadata.yaml fields check enforces.
+type Metadata struct {
+	Required []string `yaml:"required"`
+	Optional []string `yaml:"optional"`
+}
+
+// Placeholders are the values new talks are scaffolded with; they mark
+// metadata that still needs to be filled in. Events lists the placeholder
+// events: new talks get the first one, the others (such as "Unknown") are
+// what older talks used for events not known yet. Readme lists the template
+// text of the talk READMEs that is expected to be replaced.
+type Placeholders struct {
+	Events      []string `yaml:"events"`
+	Description string   `yaml:"description"`
+	Topics      []string `yaml:"topics"`
+	Readme      []string `yaml:"readme"`
+}
+
+// Outputs are the generated files, relative to the root.
+type Outputs struct {
+	Stats string `yaml:"stats"`
+	Site  string `yaml:"site"`  // directory of the static site
+	Feeds string `yaml:"feeds"` // directory of the Atom and JSON feeds
+}
+
+// Index configures the generated talks index.
+type Index struct {
+	// Updated shows how long ago each talk directory last changed in git
+	// ("updated 3 days ago"). The README then has to be regenerated after
+	// every commit to a talk, so it is off by default.
+	Updated bool `yaml:"updated"`
+}
+
+// Stats configures the statistics report.
+type Stats struct {
+	// History adds the contributors and the recently updated talks from the
+	// git history. The report then changes with every commit to a talk, so
+	// it is off by default.
+	History bool `yaml:"history"`
+}
+
+// Fields are the metadata.yaml fields known to the tooling.
+var Fields = []string{"title", "date", "event", "topics", "description", "slides_url", "video_url", "time", "timezone", "location"}
+
+// Default returns the configuration used when talks.yaml is absent.
+func Default() *Config {
+	return &Config{
+		Languages: []Language{
+			{
+				Code:        "en",
+				Readme:      "README.md",
+				TalkReadme:  "README.md",
+				IndexMarker: "## 📑 Talks Index",
+				EndMarker:   "## 🤝 Contributing",
+			},
+			{
+				Code:        "es",
+				Readme:      "docs/README-es.md",
+				TalkReadme:  "README-es.md",
+				IndexMarker: "## 📑 Índice de Charlas",
+				EndMarker:   "## 🤝 Contribuir",
+			},
+		},
+		Metadata: Metadata{
+			Required: []string{"title", "date", "topics"},
+			Optional: []string{"event", "description", "slides_url", "video_url", "time", "timezone", "location"},
+		},
+		Placeholders: Placeholders{
+			Events:      []string{"Conference/Meetup Name", "Unknown"},
+			Description: "Add a brief description of your talk here",
+			Topics:      []string{"Topic1", "Topic2", "Topic3"},
+			Readme: []string{
+				"Add your topics here",
+				"Key point 1",
+				"Add prerequisites here",
+				"Add demo instructions here",
+				"Agrega tus temas aquí",
+				"Punto clave 1",
+				"Agrega los prerequisitos aquí",
+				"Agrega las instrucciones de la demo aquí",
+				"](https://example.com)",
+			},
+		},
+		Outputs: Outputs{
+			Stats: "stats.txt",
+			Site:  "site",
+			Feeds: "feeds",
+		},
+		Taxonomy:  "topics.yaml",
+		Messages:  "i18n",
+		Templates: "templates",
+		Topics:    &topics.Registry{},
+	}
+}
+
+// Load reads talks.yaml from root, applying it over the defaults, and the
+// topic registry it points to. Missing files are not an error.
+func Load(root string) (*Config, error) {
+	cfg := Default()
+
+	path := filepath.Join(root, talkrepo.ConfigFile)
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	if err == nil {
+		if err := yaml.Unmarshal(data, cfg); err != nil {
+			return nil, fmt.Errorf("parsing %s: %w", talkrepo.ConfigFile, err)
+		}
+
+		if err := cfg.validate(); err != nil {
+			return nil, fmt.Errorf("%s: %w", talkrepo.ConfigFile, err)
+		}
+	}
+
+	registry, err := topics.Load(filepath.Join(root, cfg.Taxonomy))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", cfg.Taxonomy, err)
+	}
+	cfg.Topics = registry
+
+	return cfg, nil
+}
+
+func (c *Config) validate() error {
+	if len(c.Languages) == 0 {
+		return errors.New("at least one language is required")
+	}
+
+	seen := make(map[string]bool)
+	for i, lang := range c.Languages {
+		switch {
+		case lang.Code == "":
+			return fmt.Errorf("languages[%d]: code is required", i)
+		case seen[lang.Code]:
+			return fmt.Errorf("languages[%d]: duplicate language %q", i, lang.Code)
+		case lang.Readme == "" || lang.TalkReadme == "":
+			return fmt.Errorf("languages[%d]: readme and talk_readme are required", i)
+		}
+		seen[lang.Code] = true
+	}
+
+	for _, field := range append(c.Metadata.Required, c.Metadata.Optional...) {
+		if !knownField(field) {
+			return fmt.Errorf("metadata: unknown field %q", field)
+		}
+	}
+
+	if c.Outputs.Stats == "" {
+		return errors.New("outputs.stats is required")
+	}
+
+	if c.Outputs.Site == "" {
+		return errors.New("outputs.site is required")
+	}
+
+	if c.Outputs.Feeds == "" {
+		return errors.New("outputs.feeds is required")
+	}
+
+	if c.Taxonomy == "" {
+		return errors.New("taxonomy is required")
+	}
+
+	if c.Messages == "" {
+		return errors.New("messages is required")
+	}
+
+	if c.Templates == "" {
+		return errors.New("templates is required")
+	}
+
+	return nil
+}
+
+func knownField(name string) bool {
+	for _, field := range Fields {
+		if field == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Language returns the language with the given code.
+func (c *Config) Language(code string) (Language, bool) {
+	for _, lang := range c.Languages {
+		if lang.Code == code {
+			return lang, true
+		}
+	}
+	return Language{}, false
+}
+
+// Suffix returns what the site pages and files of the language with code
+// add before their extension: nothing for the first language, ".es" for
+// the others.
+func (c *Config) Suffix(code string) string {
+	if code == c.Languages[0].Code {
+		return ""
+	}
+	return "." + code
+}
+
+// Event returns the event new talks are scaffolded with, the first of
+// Events, or "" when there is none.
+func (p Placeholders) Event() string {
+	if len(p.Events) == 0 {
+		return ""
+	}
+	return p.Events[0]
+}
+
+// IsPlaceholderEvent reports whether event is one of the placeholder
+// events.
+func (c *Config) IsPlaceholderEvent(event string) bool {
+	for _, placeholder := range c.Placeholders.Events {
+		if event == placeholder {
+			return true
+		}
+	}
+	return false
+}
+
+// IsPlaceholderTopic reports whether topic is one of the scaffold topics.
+func (c *Config) IsPlaceholderTopic(topic string) bool {
+	for _, placeholder := range c.Placeholders.Topics {
+		if topic == placeholder {
+			return true
+		}
+	}
+	return false
+}
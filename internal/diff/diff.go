@@ -0,0 +1,166 @@
+// Package diff computes line-based differences between texts and formats
+// them as unified diffs.
+package diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// OpKind is the kind of an edit operation.
+type OpKind int
+
+const (
+	Equal  OpKind = iota // line present in both texts
+	Delete               // line only in the old text
+	Insert               // line only in the new text
+)
+
+// Op is one step of an edit script. A indexes the old lines and B the new
+// lines; only the index relevant to Kind is meaningful for Delete and Insert.
+type Op struct {
+	Kind OpKind
+	A, B int
+}
+
+// Lines returns the edit script turning a into b, based on a longest common
+// subsequence of lines.
+func Lines(a, b []string) []Op {
+	// lcs[i][j] is the length of the LCS of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var ops []Op
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			ops = append(ops, Op{Equal, i, j})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			ops = append(ops, Op{Delete, i, j})
+			i++
+		default:
+			ops = append(ops, Op{Insert, i, j})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		ops = append(ops, Op{Delete, i, j})
+	}
+	for ; j < len(b); j++ {
+		ops = append(ops, Op{Insert, i, j})
+	}
+	return ops
+}
+
+// SplitLines splits text into lines without their line endings.
+func SplitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
+
+// Unified returns the unified diff between oldText and newText with the
+// given number of context lines, or "" when they are equal.
+func Unified(oldName, newName, oldText, newText string, context int) string {
+	a, b := SplitLines(oldText), SplitLines(newText)
+	ops := Lines(a, b)
+
+	var sb strings.Builder
+	for start := 0; start < len(ops); {
+		// Find the next change
+		for start < len(ops) && ops[start].Kind == Equal {
+			start++
+		}
+		if start == len(ops) {
+			break
+		}
+
+		// Extend the hunk until more than 2*context equal lines follow
+		end := start
+		for end < len(ops) {
+			if ops[end].Kind != Equal {
+				end++
+				continue
+			}
+			run := end
+			for run < len(ops) && ops[run].Kind == Equal {
+				run++
+			}
+			if run == len(ops) || run-end > 2*context {
+				break
+			}
+			end = run
+		}
+
+		lo := max(start-context, 0)
+		for lo < start && ops[lo].Kind != Equal {
+			lo++
+		}
+		hi := min(end+context, len(ops))
+		for k := end; k < hi; k++ {
+			if ops[k].Kind != Equal {
+				hi = k
+				break
+			}
+		}
+
+		if sb.Len() == 0 {
+			fmt.Fprintf(&sb, "--- %s\n+++ %s\n", oldName, newName)
+		}
+		writeHunk(&sb, a, b, ops[lo:hi])
+		start = hi
+	}
+
+	return sb.String()
+}
+
+func writeHunk(sb *strings.Builder, a, b []string, ops []Op) {
+	oldStart, newStart := ops[0].A, ops[0].B
+	oldCount, newCount := 0, 0
+	for _, op := range ops {
+		if op.Kind != Insert {
+			oldCount++
+		}
+		if op.Kind != Delete {
+			newCount++
+		}
+	}
+
+	fmt.Fprintf(sb, "@@ -%s +%s @@\n", hunkRange(oldStart, oldCount), hunkRange(newStart, newCount))
+	for _, op := range ops {
+		switch op.Kind {
+		case Equal:
+			sb.WriteString(" " + a[op.A] + "\n")
+		case Delete:
+			sb.WriteString("-" + a[op.A] + "\n")
+		case Insert:
+			sb.WriteString("+" + b[op.B] + "\n")
+		}
+	}
+}
+
+// hunkRange formats a hunk range; start is zero-based.
+func hunkRange(start, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", start+1)
+	}
+	return fmt.Sprintf("%d,%d", start+1, count)
+}
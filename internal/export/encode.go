@@ -0,0 +1,101 @@
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Formats are the formats the catalog can be exported in.
+var Formats = []string{"json", "yaml", "csv"}
+
+// Encode renders c in format, one of Formats.
+func Encode(c Catalog, format string) ([]byte, error) {
+	switch format {
+	case "yaml":
+		return YAML(c)
+	case "csv":
+		return CSV(c)
+	default:
+		return JSON(c)
+	}
+}
+
+// JSON renders c as indented JSON.
+func JSON(c Catalog) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(c); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// YAML renders c as YAML.
+func YAML(c Catalog) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(c); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// CSV renders the talks of c as CSV, one row per talk. Localized fields
+// and README presence get one column per language (title_en, title_es,
+// ...), and topics and contributors are joined with "; ". The catalog
+// version and as-of date have no place in the rows and are left out.
+func CSV(c Catalog) ([]byte, error) {
+	header := []string{"path", "year", "date", "time", "timezone", "status"}
+	header = append(header, perLanguage("title", c.Languages)...)
+	header = append(header, perLanguage("description", c.Languages)...)
+	header = append(header, "event", "location", "topics")
+	header = append(header, perLanguage("readme", c.Languages)...)
+	header = append(header, "page_url", "source_url", "slides_url", "video_url")
+	header = append(header, "created", "updated", "contributors")
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write(header); err != nil {
+		return nil, err
+	}
+	for _, t := range c.Talks {
+		row := []string{t.Path, t.Year, t.Date, t.Time, t.Timezone, t.Status}
+		for _, lang := range c.Languages {
+			row = append(row, t.Title[lang])
+		}
+		for _, lang := range c.Languages {
+			row = append(row, t.Description[lang])
+		}
+		row = append(row, t.Event, t.Location, strings.Join(t.Topics, "; "))
+		for _, lang := range c.Languages {
+			row = append(row, strconv.FormatBool(t.Readmes[lang]))
+		}
+		row = append(row, t.Links.Page, t.Links.Source, t.Links.Slides, t.Links.Video)
+		row = append(row, t.Created, t.Updated, strings.Join(t.Contributors, "; "))
+		if err := w.Write(row); err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
+
+// perLanguage returns the column name for each language: name_en, ...
+func perLanguage(name string, langs []string) []string {
+	columns := make([]string, len(langs))
+	for i, lang := range langs {
+		columns[i] = name + "_" + lang
+	}
+	return columns
+}
@@ -0,0 +1,136 @@
+// Package export renders the whole talks catalog as a single JSON, YAML or
+// CSV document, so dashboards and other sites can consume it without
+// parsing the metadata.yaml files themselves.
+//
+// The catalog holds the normalized metadata of every talk (localized fields
+// in every configured language, canonical topics, scaffold placeholders
+// dropped) together with derived fields: year, path, past or upcoming,
+// which READMEs exist, links to the talk and, from the git history of the
+// talk directory, when it was created and last updated and by whom.
+// Version is bumped whenever a field changes meaning or is removed; new
+// fields may be added without a bump.
+package export
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/shankyjs/talks/internal/config"
+	"github.com/shankyjs/talks/internal/history"
+	"github.com/shankyjs/talks/internal/stats"
+	"github.com/shankyjs/talks/internal/talkrepo"
+)
+
+// Version is the version of the catalog format.
+const Version = 1
+
+// Status values of a talk.
+const (
+	StatusPast     = "past"
+	StatusUpcoming = "upcoming"
+)
+
+// Catalog is the exported catalog.
+type Catalog struct {
+	Version   int      `json:"version" yaml:"version"`
+	AsOf      string   `json:"as_of" yaml:"as_of"` // date past and upcoming talks are split at
+	Languages []string `json:"languages" yaml:"languages"`
+	Talks     []Talk   `json:"talks" yaml:"talks"`
+}
+
+// Talk is a talk in the catalog.
+type Talk struct {
+	Path        string            `json:"path" yaml:"path"`
+	Year        string            `json:"year" yaml:"year"`
+	Date        string            `json:"date" yaml:"date"`
+	Time        string            `json:"time,omitempty" yaml:"time,omitempty"`
+	Timezone    string            `json:"timezone,omitempty" yaml:"timezone,omitempty"`
+	Status      string            `json:"status" yaml:"status"`
+	Title       map[string]string `json:"title" yaml:"title"`                                 // by language code
+	Description map[string]string `json:"description,omitempty" yaml:"description,omitempty"` // by language code
+	Event       string            `json:"event,omitempty" yaml:"event,omitempty"`
+	Location    string            `json:"location,omitempty" yaml:"location,omitempty"`
+	Topics      []string          `json:"topics" yaml:"topics"`   // canonical names
+	Readmes     map[string]bool   `json:"readmes" yaml:"readmes"` // by language code
+	Links       Links             `json:"links" yaml:"links"`
+
+	// From the git history; empty for talks that are not committed yet
+	Created      string   `json:"created,omitempty" yaml:"created,omitempty"` // YYYY-MM-DD of the first commit
+	Updated      string   `json:"updated,omitempty" yaml:"updated,omitempty"` // YYYY-MM-DD of the last commit
+	Contributors []string `json:"contributors,omitempty" yaml:"contributors,omitempty"`
+}
+
+// Links are the links of a talk. Page and Source are only set when
+// talks.yaml has site_url and source_url.
+type Links struct {
+	Page   string `json:"page,omitempty" yaml:"page,omitempty"`
+	Source string `json:"source,omitempty" yaml:"source,omitempty"`
+	Slides string `json:"slides,omitempty" yaml:"slides,omitempty"`
+	Video  string `json:"video,omitempty" yaml:"video,omitempty"`
+}
+
+// New builds the catalog of talks, newest first. Talks dated before asOf
+// (YYYY-MM-DD) are past talks. hist is the git history of each talk path
+// (see history.Load); it may be nil.
+func New(root string, cfg *config.Config, talks []talkrepo.Talk, asOf string, hist map[string]history.Talk) Catalog {
+	c := Catalog{Version: Version, AsOf: asOf, Talks: []Talk{}}
+	for _, lang := range cfg.Languages {
+		c.Languages = append(c.Languages, lang.Code)
+	}
+
+	sorted := append([]talkrepo.Talk(nil), talks...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Date != sorted[j].Date {
+			return sorted[i].Date > sorted[j].Date
+		}
+		return sorted[i].Path < sorted[j].Path
+	})
+
+	for _, t := range sorted {
+		talk := Talk{
+			Path:     t.Path,
+			Year:     t.Year,
+			Date:     t.Date,
+			Time:     t.Time,
+			Timezone: t.Timezone,
+			Status:   StatusPast,
+			Title:    make(map[string]string),
+			Location: t.Location,
+			Topics:   append([]string{}, stats.Topics(t, cfg)...),
+			Readmes:  make(map[string]bool),
+			Links:    Links{Slides: t.SlidesURL, Video: t.VideoURL},
+
+			Created:      hist[t.Path].Created,
+			Updated:      hist[t.Path].Updated,
+			Contributors: hist[t.Path].Contributors,
+		}
+		if stats.IsUpcoming(t, asOf) {
+			talk.Status = StatusUpcoming
+		}
+		talk.Event = stats.Event(t, cfg)
+
+		for _, lang := range cfg.Languages {
+			talk.Title[lang.Code] = t.Title.Text(lang.Code)
+			if description := t.Description.Text(lang.Code); description != "" && description != cfg.Placeholders.Description {
+				if talk.Description == nil {
+					talk.Description = make(map[string]string)
+				}
+				talk.Description[lang.Code] = description
+			}
+
+			_, err := os.Stat(filepath.Join(root, filepath.FromSlash(t.Path), lang.TalkReadme))
+			talk.Readmes[lang.Code] = err == nil
+		}
+
+		if cfg.SiteURL != "" {
+			talk.Links.Page = strings.TrimSuffix(cfg.SiteURL, "/") + "/" + t.Path + "/index.html"
+		}
+		if cfg.SourceURL != "" {
+			talk.Links.Source = strings.TrimSuffix(cfg.SourceURL, "/") + "/" + t.Path
+		}
+		c.Talks = append(c.Talks, talk)
+	}
+	return c
+}
@@ -0,0 +1,94 @@
+package feed
+
+import (
+	"encoding/xml"
+)
+
+type atomFeed struct {
+	XMLName  xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Lang     string      `xml:"xml:lang,attr,omitempty"`
+	Title    string      `xml:"title"`
+	Subtitle string      `xml:"subtitle,omitempty"`
+	ID       string      `xml:"id"`
+	Links    []atomLink  `xml:"link"`
+	Updated  string      `xml:"updated"`
+	Author   atomPerson  `xml:"author"`
+	Entries  []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Rel   string `xml:"rel,attr,omitempty"`
+	Type  string `xml:"type,attr,omitempty"`
+	Href  string `xml:"href,attr"`
+	Title string `xml:"title,attr,omitempty"`
+}
+
+type atomPerson struct {
+	Name string `xml:"name"`
+}
+
+type atomEntry struct {
+	ID         string         `xml:"id"`
+	Title      string         `xml:"title"`
+	Links      []atomLink     `xml:"link"`
+	Published  string         `xml:"published"`
+	Updated    string         `xml:"updated"`
+	Summary    string         `xml:"summary,omitempty"`
+	Content    atomContent    `xml:"content"`
+	Categories []atomCategory `xml:"category"`
+}
+
+type atomContent struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",chardata"`
+}
+
+type atomCategory struct {
+	Term  string `xml:"term,attr"`
+	Label string `xml:"label,attr,omitempty"`
+}
+
+// Atom renders f as an Atom 1.0 document.
+func Atom(f Feed) ([]byte, error) {
+	doc := atomFeed{
+		Lang:     f.Lang,
+		Title:    f.Title,
+		Subtitle: f.Description,
+		ID:       f.ID,
+		Links:    []atomLink{{Rel: "alternate", Type: "text/html", Href: f.HomeURL}},
+		Updated:  timestamp(f.Updated),
+		Author:   atomPerson{f.Author},
+	}
+	if f.AtomURL != "" {
+		doc.Links = append(doc.Links, atomLink{Rel: "self", Type: "application/atom+xml", Href: f.AtomURL})
+	}
+	if doc.Updated == "" {
+		// Atom requires an updated date even for an empty feed
+		doc.Updated = timestamp("1970-01-01")
+	}
+
+	for _, e := range f.Entries {
+		entry := atomEntry{
+			ID:        e.ID,
+			Title:     e.Title,
+			Links:     []atomLink{{Rel: "alternate", Type: "text/html", Href: e.URL}},
+			Published: timestamp(e.Date),
+			Updated:   timestamp(e.Date),
+			Summary:   e.Description,
+			Content:   atomContent{"html", contentHTML(e)},
+		}
+		for _, l := range e.Links {
+			entry.Links = append(entry.Links, atomLink{Rel: "related", Href: l.URL, Title: l.Title})
+		}
+		for _, t := range e.Topics {
+			entry.Categories = append(entry.Categories, atomCategory{t.Term, t.Label})
+		}
+		doc.Entries = append(doc.Entries, entry)
+	}
+
+	out, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), append(out, '\n')...), nil
+}
@@ -0,0 +1,192 @@
+// Package feed renders the talks as Atom and JSON Feed documents, so people
+// can subscribe to new talks.
+//
+// Entry IDs are derived from the talk path under the site URL (site_url in
+// talks.yaml, else source_url), so they stay the same across runs and
+// languages as long as the talk directory is not renamed.
+package feed
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"sort"
+	"strings"
+
+	"github.com/shankyjs/talks/internal/config"
+	"github.com/shankyjs/talks/internal/messages"
+	"github.com/shankyjs/talks/internal/stats"
+	"github.com/shankyjs/talks/internal/talkrepo"
+)
+
+// File names of the feeds of the default language. Other languages add
+// their code before the extension, as the site does: feed.es.xml.
+const (
+	AtomFile = "feed.xml"
+	JSONFile = "feed.json"
+)
+
+// ErrNoBaseURL is returned by New when talks.yaml sets neither site_url nor
+// source_url, which entry IDs and links are built from.
+var ErrNoBaseURL = errors.New("feeds need site_url or source_url in talks.yaml for entry IDs and links")
+
+// Feed is a feed of talks in one language, independent of its format.
+type Feed struct {
+	Lang        string
+	Title       string
+	Description string
+	Author      string
+	ID          string
+	HomeURL     string
+	AtomURL     string // empty without site_url
+	JSONURL     string // empty without site_url
+	Updated     string // date of the newest talk, at most the as-of date, YYYY-MM-DD
+	Entries     []Entry
+}
+
+// Entry is a talk in a feed.
+type Entry struct {
+	ID          string
+	URL         string
+	Title       string
+	Date        string // YYYY-MM-DD
+	Event       string
+	Description string
+	Topics      []Topic
+	Links       []Link // slides, video and materials
+}
+
+// Topic is a canonical topic with its display name.
+type Topic struct {
+	Term  string
+	Label string
+}
+
+// Link is a titled link of an entry.
+type Link struct {
+	Title string
+	URL   string
+}
+
+// New builds the feed of talks in lang. suffix is what the feed file names
+// add ("" for a single feed, ".es" for the Spanish one of split feeds);
+// links to the site always point at the pages in lang. asOf (YYYY-MM-DD)
+// caps the feed's updated date, so upcoming talks do not date it in the
+// future.
+func New(root string, cfg *config.Config, talks []talkrepo.Talk, lang, suffix, asOf string) (Feed, error) {
+	base := strings.TrimSuffix(cfg.SiteURL, "/")
+	if base == "" {
+		base = strings.TrimSuffix(cfg.SourceURL, "/")
+	}
+	if base == "" {
+		return Feed{}, ErrNoBaseURL
+	}
+
+	msgs, err := messages.Load(root, cfg.Messages, lang)
+	if err != nil {
+		return Feed{}, err
+	}
+
+	f := Feed{
+		Lang:        lang,
+		Title:       msgs.Get("site.title"),
+		Description: msgs.Get("index.intro"),
+		Author:      cfg.Author,
+		ID:          base + "/",
+		HomeURL:     base + "/",
+	}
+	if f.Author == "" {
+		f.Author = f.Title
+	}
+	page := cfg.Suffix(lang)
+	if cfg.SiteURL != "" {
+		f.HomeURL = base + "/index" + page + ".html"
+		f.AtomURL = base + "/" + fileName(AtomFile, suffix)
+		f.JSONURL = base + "/" + fileName(JSONFile, suffix)
+	}
+
+	sorted := append([]talkrepo.Talk(nil), talks...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Date != sorted[j].Date {
+			return sorted[i].Date > sorted[j].Date
+		}
+		return sorted[i].Path < sorted[j].Path
+	})
+
+	for _, t := range sorted {
+		entry := Entry{
+			ID:    base + "/" + t.Path + "/",
+			URL:   base + "/" + t.Path,
+			Title: t.Title.Text(lang),
+			Date:  t.Date,
+		}
+		if cfg.SiteURL != "" {
+			entry.URL = base + "/" + t.Path + "/index" + page + ".html"
+		}
+		entry.Event = stats.Event(t, cfg)
+		if description := t.Description.Text(lang); description != cfg.Placeholders.Description {
+			entry.Description = description
+		}
+		for _, topic := range stats.Topics(t, cfg) {
+			entry.Topics = append(entry.Topics, Topic{topic, cfg.Topics.DisplayName(topic, lang)})
+		}
+		if t.SlidesURL != "" {
+			entry.Links = append(entry.Links, Link{msgs.Get("site.slides"), t.SlidesURL})
+		}
+		if t.VideoURL != "" {
+			entry.Links = append(entry.Links, Link{msgs.Get("site.video"), t.VideoURL})
+		}
+		if cfg.SourceURL != "" {
+			entry.Links = append(entry.Links, Link{msgs.Get("site.source"), strings.TrimSuffix(cfg.SourceURL, "/") + "/" + t.Path})
+		}
+		f.Entries = append(f.Entries, entry)
+	}
+	if len(f.Entries) > 0 {
+		f.Updated = f.Entries[0].Date
+	}
+	if f.Updated > asOf {
+		f.Updated = asOf
+	}
+	return f, nil
+}
+
+// fileName adds suffix before the extension of name.
+func fileName(name, suffix string) string {
+	i := strings.LastIndex(name, ".")
+	return name[:i] + suffix + name[i:]
+}
+
+// FileNames returns the Atom and JSON Feed file names for suffix.
+func FileNames(suffix string) (atom, json string) {
+	return fileName(AtomFile, suffix), fileName(JSONFile, suffix)
+}
+
+// timestamp turns a talk date into an RFC 3339 timestamp.
+func timestamp(date string) string {
+	if date == "" {
+		return ""
+	}
+	return date + "T00:00:00Z"
+}
+
+// contentHTML is the HTML body of an entry: its date, event, description
+// and links.
+func contentHTML(e Entry) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "<p>📅 %s", html.EscapeString(e.Date))
+	if e.Event != "" {
+		fmt.Fprintf(&b, " · 🎪 %s", html.EscapeString(e.Event))
+	}
+	b.WriteString("</p>")
+	if e.Description != "" {
+		fmt.Fprintf(&b, "<p>%s</p>", html.EscapeString(e.Description))
+	}
+	if len(e.Links) > 0 {
+		b.WriteString("<ul>")
+		for _, l := range e.Links {
+			fmt.Fprintf(&b, `<li><a href="%s">%s</a></li>`, html.EscapeString(l.URL), html.EscapeString(l.Title))
+		}
+		b.WriteString("</ul>")
+	}
+	return b.String()
+}
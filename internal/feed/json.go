@@ -0,0 +1,92 @@
+package feed
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// JSONFeedVersion is the JSON Feed version JSON renders.
+const JSONFeedVersion = "https://jsonfeed.org/version/1.1"
+
+type jsonFeed struct {
+	Version     string       `json:"version"`
+	Title       string       `json:"title"`
+	HomePageURL string       `json:"home_page_url"`
+	FeedURL     string       `json:"feed_url,omitempty"`
+	Description string       `json:"description,omitempty"`
+	Language    string       `json:"language,omitempty"`
+	Authors     []jsonAuthor `json:"authors"`
+	Items       []jsonItem   `json:"items"`
+}
+
+type jsonAuthor struct {
+	Name string `json:"name"`
+}
+
+type jsonItem struct {
+	ID            string   `json:"id"`
+	URL           string   `json:"url"`
+	Title         string   `json:"title"`
+	ContentHTML   string   `json:"content_html"`
+	Summary       string   `json:"summary,omitempty"`
+	DatePublished string   `json:"date_published"`
+	Tags          []string `json:"tags,omitempty"`
+	Talk          jsonTalk `json:"_talks"`
+}
+
+// jsonTalk is the "_talks" extension of an item, with the talk fields
+// JSON Feed has no place for.
+type jsonTalk struct {
+	Date   string     `json:"date"`
+	Event  string     `json:"event,omitempty"`
+	Topics []string   `json:"topics,omitempty"` // canonical names
+	Links  []jsonLink `json:"links,omitempty"`
+}
+
+type jsonLink struct {
+	Title string `json:"title"`
+	URL   string `json:"url"`
+}
+
+// JSON renders f as a JSON Feed 1.1 document.
+func JSON(f Feed) ([]byte, error) {
+	doc := jsonFeed{
+		Version:     JSONFeedVersion,
+		Title:       f.Title,
+		HomePageURL: f.HomeURL,
+		FeedURL:     f.JSONURL,
+		Description: f.Description,
+		Language:    f.Lang,
+		Authors:     []jsonAuthor{{f.Author}},
+		Items:       []jsonItem{},
+	}
+
+	for _, e := range f.Entries {
+		item := jsonItem{
+			ID:            e.ID,
+			URL:           e.URL,
+			Title:         e.Title,
+			ContentHTML:   contentHTML(e),
+			Summary:       e.Description,
+			DatePublished: timestamp(e.Date),
+			Talk:          jsonTalk{Date: e.Date, Event: e.Event},
+		}
+		for _, t := range e.Topics {
+			item.Tags = append(item.Tags, t.Label)
+			item.Talk.Topics = append(item.Talk.Topics, t.Term)
+		}
+		for _, l := range e.Links {
+			item.Talk.Links = append(item.Talk.Links, jsonLink{l.Title, l.URL})
+		}
+		doc.Items = append(doc.Items, item)
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,123 @@
+// Package history reads the git history of the talk directories from the
+// local clone, using the git command.
+//
+// Everything here is best effort: outside a git repository, or without git
+// installed, there is simply no history and the callers leave the derived
+// fields out. A shallow clone is the exception: its history is cut short,
+// so the dates would be wrong rather than missing, and Load reports
+// ErrShallow instead.
+package history
+
+import (
+	"bytes"
+	"errors"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shankyjs/talks/internal/talkrepo"
+)
+
+// Layout is the format of the returned dates (UTC).
+const Layout = "2006-01-02"
+
+// ErrShallow is returned by Load for a shallow clone.
+var ErrShallow = errors.New("the git history is shallow; fetch all of it (git fetch --unshallow, or fetch-depth: 0 in actions/checkout) for created and updated dates and contributors")
+
+// Talk is the git history of a talk directory.
+type Talk struct {
+	Created      string   // author date of the first commit touching the directory
+	Updated      string   // author date of the last commit touching the directory
+	Contributors []string // commit authors (as mapped by .mailmap), most commits first
+}
+
+// Load returns the history of the directory of each of talks, keyed by
+// talk path. Talks that are not committed yet are missing from the map, and
+// so is every talk when root is not a git repository. In a shallow clone
+// the map is empty and the error is ErrShallow.
+func Load(root string, talks []talkrepo.Talk) (map[string]Talk, error) {
+	history := make(map[string]Talk)
+	if len(talks) == 0 {
+		return history, nil
+	}
+	if out, ok := git(root, "rev-parse", "--is-shallow-repository"); ok && strings.TrimSpace(string(out)) == "true" {
+		return history, ErrShallow
+	}
+
+	// --relative lists files relative to root, which need not be the top of
+	// the repository
+	args := []string{"log", "--no-renames", "--relative", "--format=%x00%at%x09%aN", "--name-only", "--"}
+	dirs := make(map[string]bool, len(talks))
+	for _, t := range talks {
+		dirs[t.Path] = true
+		args = append(args, t.Path)
+	}
+
+	out, ok := git(root, args...)
+	if !ok {
+		return history, nil
+	}
+
+	commits := make(map[string]map[string]int) // talk path -> author -> commits
+	for _, commit := range bytes.Split(out, []byte{0}) {
+		lines := strings.Split(strings.TrimSpace(string(commit)), "\n")
+		stamp, author, _ := strings.Cut(lines[0], "\t")
+		seconds, err := strconv.ParseInt(stamp, 10, 64)
+		if err != nil {
+			continue
+		}
+		date := time.Unix(seconds, 0).UTC().Format(Layout)
+
+		touched := make(map[string]bool)
+		for _, file := range lines[1:] {
+			// Talk directories are always two levels deep: YYYY/slug
+			parts := strings.SplitN(strings.TrimSpace(file), "/", 3)
+			if len(parts) == 3 && dirs[parts[0]+"/"+parts[1]] {
+				touched[parts[0]+"/"+parts[1]] = true
+			}
+		}
+
+		// Author dates need not follow the commit order, so compare them
+		for p := range touched {
+			t := history[p]
+			if t.Created == "" || date < t.Created {
+				t.Created = date
+			}
+			if date > t.Updated {
+				t.Updated = date
+			}
+			history[p] = t
+
+			if commits[p] == nil {
+				commits[p] = make(map[string]int)
+			}
+			commits[p][author]++
+		}
+	}
+
+	for p, authors := range commits {
+		t := history[p]
+		for author := range authors {
+			t.Contributors = append(t.Contributors, author)
+		}
+		sort.Slice(t.Contributors, func(i, j int) bool {
+			x, y := t.Contributors[i], t.Contributors[j]
+			if authors[x] != authors[y] {
+				return authors[x] > authors[y]
+			}
+			return x < y
+		})
+		history[p] = t
+	}
+	return history, nil
+}
+
+// git runs git in root and returns its output.
+func git(root string, args ...string) ([]byte, bool) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = root
+	out, err := cmd.Output()
+	return out, err == nil
+}
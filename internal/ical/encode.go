@@ -0,0 +1,103 @@
+package ical
+
+import (
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// maxLine is the longest content line RFC 5545 allows, in octets, before
+// it has to be folded.
+const maxLine = 75
+
+// Encode renders c as an iCalendar document.
+func Encode(c Calendar) []byte {
+	var w writer
+	w.line("BEGIN:VCALENDAR")
+	w.line("VERSION:2.0")
+	w.line("PRODID:" + ProdID)
+	w.line("CALSCALE:GREGORIAN")
+	w.line("METHOD:PUBLISH")
+	w.line("X-WR-CALNAME:" + text(c.Name))
+	if c.Description != "" {
+		w.line("X-WR-CALDESC:" + text(c.Description))
+	}
+
+	stamp := c.Stamp.UTC().Format("20060102T150405Z")
+	for _, e := range c.Events {
+		w.line("BEGIN:VEVENT")
+		w.line("UID:" + text(e.UID))
+		w.line("DTSTAMP:" + stamp)
+		w.line("DTSTART" + e.dateTime(e.Start))
+		w.line("DTEND" + e.dateTime(e.End))
+		w.line("SUMMARY:" + text(e.Summary))
+		if e.Description != "" {
+			w.line("DESCRIPTION:" + text(e.Description))
+		}
+		if e.Location != "" {
+			w.line("LOCATION:" + text(e.Location))
+		}
+		if len(e.Categories) > 0 {
+			categories := make([]string, len(e.Categories))
+			for i, c := range e.Categories {
+				categories[i] = text(c)
+			}
+			w.line("CATEGORIES:" + strings.Join(categories, ","))
+		}
+		if e.URL != "" {
+			w.line("URL:" + e.URL)
+		}
+		w.line("END:VEVENT")
+	}
+
+	w.line("END:VCALENDAR")
+	return []byte(w.String())
+}
+
+// dateTime formats t as the parameters and value of a DTSTART or DTEND
+// property of e.
+func (e Event) dateTime(t time.Time) string {
+	switch {
+	case e.AllDay:
+		return ";VALUE=DATE:" + t.Format("20060102")
+	case e.Floating:
+		return ":" + t.Format("20060102T150405")
+	default:
+		return ":" + t.UTC().Format("20060102T150405Z")
+	}
+}
+
+// text escapes s as an iCalendar TEXT value.
+func text(s string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	).Replace(s)
+}
+
+// writer writes content lines, folded and ending in CRLF as RFC 5545
+// requires.
+type writer struct {
+	strings.Builder
+}
+
+func (w *writer) line(s string) {
+	limit := maxLine
+	for len(s) > limit {
+		// Fold at the last rune boundary that fits, so no UTF-8 sequence is
+		// split across lines
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		w.WriteString(s[:cut])
+		w.WriteString("\r\n ")
+		s = s[cut:]
+		limit = maxLine - 1 // the leading space counts
+	}
+	w.WriteString(s)
+	w.WriteString("\r\n")
+}
@@ -0,0 +1,180 @@
+// Package ical renders the talks as an iCalendar (RFC 5545) file, so people
+// can subscribe to the speaking calendar.
+//
+// Every talk is one VEVENT. Its UID is derived from the talk path, so it
+// stays the same across runs and languages as long as the talk directory is
+// not renamed, and calendar apps update the event instead of adding a new
+// one. Talks with a time in their metadata are timed events (one hour
+// long); the others are all-day events on their date.
+package ical
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shankyjs/talks/internal/config"
+	"github.com/shankyjs/talks/internal/messages"
+	"github.com/shankyjs/talks/internal/stats"
+	"github.com/shankyjs/talks/internal/talkrepo"
+)
+
+// File is the calendar file name of the default language. Other languages
+// add their code before the extension, as the site does: talks.es.ics.
+const File = "talks.ics"
+
+// Duration is the length of timed events; metadata has no end time.
+const Duration = time.Hour
+
+// ProdID identifies the program that wrote a calendar.
+const ProdID = "-//shankyjs//talks//EN"
+
+// Calendar is a calendar of talks in one language, independent of its
+// encoding.
+type Calendar struct {
+	Lang        string
+	Name        string
+	Description string
+	Stamp       time.Time // DTSTAMP of every event
+	Events      []Event
+}
+
+// Event is a talk in a calendar.
+type Event struct {
+	UID         string
+	URL         string
+	Summary     string
+	Description string
+	Location    string
+	Categories  []string
+	Start       time.Time
+	End         time.Time
+	AllDay      bool // Start and End are dates
+	Floating    bool // Start and End are local times without a time zone
+}
+
+// New builds the calendar of talks in lang, linking to the site pages in
+// lang. asOf (YYYY-MM-DD) is the DTSTAMP of the events, so the same talks always
+// produce the same file.
+//
+// Talks whose date, time or time zone cannot be parsed are left out of the
+// calendar and returned as skipped, one error per talk, as talkrepo.Load
+// does with talks it cannot load. The returned error is only set when the
+// calendar cannot be built at all.
+func New(root string, cfg *config.Config, talks []talkrepo.Talk, lang, asOf string) (c Calendar, skipped []error, err error) {
+	msgs, err := messages.Load(root, cfg.Messages, lang)
+	if err != nil {
+		return Calendar{}, nil, err
+	}
+
+	stamp, err := time.Parse("2006-01-02", asOf)
+	if err != nil {
+		return Calendar{}, nil, fmt.Errorf("as-of date: %w", err)
+	}
+
+	c = Calendar{
+		Lang:        lang,
+		Name:        msgs.Get("site.title"),
+		Description: msgs.Get("index.intro"),
+		Stamp:       stamp,
+	}
+	if cfg.Author != "" {
+		c.Name = cfg.Author + " · " + c.Name
+	}
+
+	host := "talks"
+	for _, base := range []string{cfg.SiteURL, cfg.SourceURL} {
+		if u, err := url.Parse(base); err == nil && u.Host != "" {
+			host = u.Host
+			break
+		}
+	}
+
+	sorted := append([]talkrepo.Talk(nil), talks...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Date != sorted[j].Date {
+			return sorted[i].Date < sorted[j].Date
+		}
+		return sorted[i].Path < sorted[j].Path
+	})
+
+	for _, t := range sorted {
+		event := Event{
+			UID:      t.Path + "@" + host,
+			Summary:  t.Title.Text(lang),
+			Location: t.Location,
+		}
+		if err := event.schedule(t); err != nil {
+			skipped = append(skipped, fmt.Errorf("%s: %w", t.Path, err))
+			continue
+		}
+
+		switch {
+		case cfg.SourceURL != "":
+			event.URL = strings.TrimSuffix(cfg.SourceURL, "/") + "/" + t.Path
+		case cfg.SiteURL != "":
+			event.URL = strings.TrimSuffix(cfg.SiteURL, "/") + "/" + t.Path + "/index" + cfg.Suffix(lang) + ".html"
+		}
+
+		var details []string
+		if description := t.Description.Text(lang); description != "" && description != cfg.Placeholders.Description {
+			details = append(details, description)
+		}
+		if name := stats.Event(t, cfg); name != "" {
+			details = append(details, "🎪 "+name)
+			if event.Location == "" {
+				event.Location = name
+			}
+		}
+		if t.SlidesURL != "" {
+			details = append(details, msgs.Get("site.slides")+": "+t.SlidesURL)
+		}
+		if t.VideoURL != "" {
+			details = append(details, msgs.Get("site.video")+": "+t.VideoURL)
+		}
+		if event.URL != "" {
+			details = append(details, msgs.Get("site.source")+": "+event.URL)
+		}
+		event.Description = strings.Join(details, "\n")
+
+		for _, topic := range stats.Topics(t, cfg) {
+			event.Categories = append(event.Categories, cfg.Topics.DisplayName(topic, lang))
+		}
+		c.Events = append(c.Events, event)
+	}
+	return c, skipped, nil
+}
+
+// schedule sets the start and end of e from the date, time and time zone
+// of t.
+func (e *Event) schedule(t talkrepo.Talk) error {
+	if t.Time == "" {
+		date, err := time.Parse("2006-01-02", t.Date)
+		if err != nil {
+			return fmt.Errorf("date: %q is not a valid date", t.Date)
+		}
+		e.Start, e.End, e.AllDay = date, date.AddDate(0, 0, 1), true
+		return nil
+	}
+
+	loc := time.UTC
+	if t.Timezone != "" {
+		var err error
+		if loc, err = time.LoadLocation(t.Timezone); err != nil {
+			return fmt.Errorf("timezone: %q is not a known time zone", t.Timezone)
+		}
+	}
+	start, err := time.ParseInLocation("2006-01-02 15:04", t.Date+" "+t.Time, loc)
+	if err != nil {
+		return fmt.Errorf("time: %q on %q is not a valid date and time", t.Time, t.Date)
+	}
+	e.Start, e.End, e.Floating = start, start.Add(Duration), t.Timezone == ""
+	return nil
+}
+
+// FileName returns the calendar file name for suffix.
+func FileName(suffix string) string {
+	return strings.TrimSuffix(File, ".ics") + suffix + ".ics"
+}
@@ -0,0 +1,300 @@
+package index
+
+import (
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shankyjs/talks/internal/config"
+	"github.com/shankyjs/talks/internal/history"
+	"github.com/shankyjs/talks/internal/stats"
+	"github.com/shankyjs/talks/internal/talkrepo"
+	"github.com/shankyjs/talks/internal/topics"
+)
+
+// Data is the data passed to the index templates. Text is already in the
+// language of the README being rendered.
+type Data struct {
+	Lang      string  // language code, e.g. "es"
+	Talks     []Talk  // every talk, newest first
+	Materials []Talk  // talks with materials, newest first
+	Years     []Year  // talks grouped by year, newest first
+	Topics    []Topic // topic tree, sorted by name
+	Events    []Count // events by number of talks, most talks first
+	Stats     Stats
+}
+
+// Talk is a talk as shown in the index.
+type Talk struct {
+	Title       string
+	Description string
+	Date        string // YYYY-MM-DD
+	Year        string
+	Path        string   // talk directory, relative to the root
+	Event       string   // empty for placeholder events
+	Topics      []string // canonical topics without placeholders, as display names
+	SlidesURL   string
+	VideoURL    string
+	Readmes     []Link     // talk README of every language
+	Upcoming    bool       // the talk has not happened yet
+	Materials   []Material // slides, video and what the talk directory contains
+
+	// From the git history, when the index shows it (index.updated in
+	// talks.yaml); empty otherwise and for talks not committed yet
+	Created      string // YYYY-MM-DD of the first commit
+	Updated      string // YYYY-MM-DD of the last commit
+	UpdatedDays  int    // days from Updated to today
+	Contributors []string
+}
+
+// Kinds of material that come from the metadata rather than from the talk
+// directory; the others are the talkrepo.Material kinds.
+const (
+	MaterialSlides = "slides"
+	MaterialVideo  = "video"
+)
+
+// MaterialIcons are the icons of the material kinds in the index.
+var MaterialIcons = map[string]string{
+	MaterialSlides:             "🎞️",
+	MaterialVideo:              "🎥",
+	talkrepo.MaterialGo:        "🐹",
+	talkrepo.MaterialTerraform: "🏗️",
+	talkrepo.MaterialHelm:      "☸️",
+	talkrepo.MaterialKustomize: "🔄",
+	talkrepo.MaterialMakefile:  "🛠️",
+}
+
+// Material is a kind of material of a talk. Its message key is
+// "materials." followed by Kind.
+type Material struct {
+	Kind  string
+	Icon  string
+	URL   string // link from the README: the slides or video URL, else where the material is, e.g. ./2025/talk/apps
+	Links []Link // every directory holding the material, labelled relative to the talk directory; empty for files and URLs
+}
+
+// Link is a labelled link relative to the root.
+type Link struct {
+	Label string
+	URL   string
+}
+
+// Year is a year directory with its talks.
+type Year struct {
+	Year  string
+	Talks []Talk
+}
+
+// Topic is a node of the topic tree. Topics with a parent in the topic
+// registry are children of it; parents can have no talks of their own.
+type Topic struct {
+	Key      string // canonical name
+	Name     string // display name
+	Depth    int    // 0 for top-level topics
+	Talks    []Talk
+	Children []Topic
+}
+
+// Count is a name with the number of talks it applies to.
+type Count struct {
+	Name  string
+	Count int
+}
+
+// Stats summarizes the talks, from the same stats.Report as the detailed
+// statistics report.
+type Stats struct {
+	Total       int
+	Past        int
+	Upcoming    int
+	ActiveYears int
+	TopTopics   []Count // the stats.SummaryTopics most used topics
+	TopicCounts []Count // every topic, most used first
+}
+
+// NewData builds the template data for lang. Talks dated before today are
+// past talks. Talks keeps the order of talks. hist is the git history of
+// each talk path (see history.Load); it may be nil.
+func NewData(talks []talkrepo.Talk, cfg *config.Config, lang, today string, hist map[string]history.Talk) Data {
+	data := Data{Lang: lang}
+	registry := cfg.Topics
+
+	yearIndex := make(map[string]int)
+	topicTalks := make(map[string][]Talk)
+
+	for _, t := range talks {
+		talk := Talk{
+			Title:       t.Title.Text(lang),
+			Description: t.Description.Text(lang),
+			Date:        t.Date,
+			Year:        t.Year,
+			Path:        t.Path,
+			Event:       stats.Event(t, cfg),
+			SlidesURL:   t.SlidesURL,
+			VideoURL:    t.VideoURL,
+			Upcoming:    stats.IsUpcoming(t, today),
+		}
+		if h, ok := hist[t.Path]; ok {
+			talk.Created, talk.Updated, talk.Contributors = h.Created, h.Updated, h.Contributors
+			talk.UpdatedDays = days(h.Updated, today)
+		}
+		for _, l := range cfg.Languages {
+			talk.Readmes = append(talk.Readmes, Link{strings.ToUpper(l.Code), path.Join(t.Path, l.TalkReadme)})
+		}
+		talk.Materials = materials(t)
+
+		for _, topic := range stats.Topics(t, cfg) {
+			talk.Topics = append(talk.Topics, registry.DisplayName(topic, lang))
+			topicTalks[topic] = append(topicTalks[topic], talk)
+		}
+
+		data.Talks = append(data.Talks, talk)
+		if len(talk.Materials) > 0 {
+			data.Materials = append(data.Materials, talk)
+		}
+		i, ok := yearIndex[t.Year]
+		if !ok {
+			i = len(data.Years)
+			yearIndex[t.Year] = i
+			data.Years = append(data.Years, Year{Year: t.Year})
+		}
+		data.Years[i].Talks = append(data.Years[i].Talks, talk)
+	}
+
+	// Sort years descending
+	sort.Slice(data.Years, func(i, j int) bool {
+		return data.Years[i].Year > data.Years[j].Year
+	})
+
+	data.Topics = topicTree(topicTalks, registry, lang)
+
+	report := stats.Compute(talks, cfg, today, nil)
+	data.Stats = Stats{
+		Total:       report.Total,
+		Past:        report.Past,
+		Upcoming:    report.Upcoming,
+		ActiveYears: len(report.Years),
+		TopicCounts: counts(report.Topics, func(topic string) string {
+			return registry.DisplayName(topic, lang)
+		}),
+	}
+	data.Stats.TopTopics = data.Stats.TopicCounts[:min(stats.SummaryTopics, len(data.Stats.TopicCounts))]
+	data.Events = counts(report.Events, nil)
+
+	return data
+}
+
+// materials returns the slides and video of t followed by the materials in
+// its directory.
+func materials(t talkrepo.Talk) []Material {
+	var list []Material
+	for _, m := range []struct{ kind, url string }{{MaterialSlides, t.SlidesURL}, {MaterialVideo, t.VideoURL}} {
+		if m.url != "" {
+			list = append(list, Material{Kind: m.kind, Icon: MaterialIcons[m.kind], URL: m.url})
+		}
+	}
+
+	for _, m := range t.Materials {
+		material := Material{
+			Kind: m.Kind,
+			Icon: MaterialIcons[m.Kind],
+			URL:  "./" + path.Join(t.Path, commonDir(m.Paths)),
+		}
+		if m.Kind != talkrepo.MaterialMakefile {
+			for _, p := range m.Paths {
+				material.Links = append(material.Links, Link{p, path.Join(t.Path, p)})
+			}
+		}
+		list = append(list, material)
+	}
+	return list
+}
+
+// commonDir returns the deepest directory holding every one of paths,
+// which is the path itself when there is only one.
+func commonDir(paths []string) string {
+	dir := paths[0]
+	for _, p := range paths[1:] {
+		for dir != "." && p != dir && !strings.HasPrefix(p, dir+"/") {
+			dir = path.Dir(dir)
+		}
+	}
+	return dir
+}
+
+// days returns the number of days from one date (YYYY-MM-DD) to another,
+// or 0 when to is not after from.
+func days(from, to string) int {
+	start, err := time.Parse("2006-01-02", from)
+	if err != nil {
+		return 0
+	}
+	end, err := time.Parse("2006-01-02", to)
+	if err != nil || !end.After(start) {
+		return 0
+	}
+	return int(end.Sub(start).Hours() / 24)
+}
+
+// counts converts report counts, renaming each with name when it is not
+// nil.
+func counts(report []stats.Count, name func(string) string) []Count {
+	counts := make([]Count, 0, len(report))
+	for _, c := range report {
+		if name != nil {
+			counts = append(counts, Count{name(c.Name), c.Count})
+		} else {
+			counts = append(counts, Count{c.Name, c.Count})
+		}
+	}
+	return counts
+}
+
+// topicTree nests the topics in use under their parents from the registry,
+// adding parents that have no talks of their own.
+func topicTree(topicTalks map[string][]Talk, registry *topics.Registry, lang string) []Topic {
+	shown := make(map[string]bool)
+	children := make(map[string][]string)
+	var roots []string
+	var add func(topic string)
+	add = func(topic string) {
+		if shown[topic] {
+			return
+		}
+		shown[topic] = true
+		if parent := registry.Parent(topic); parent != "" {
+			children[parent] = append(children[parent], topic)
+			add(parent)
+		} else {
+			roots = append(roots, topic)
+		}
+	}
+	for topic := range topicTalks {
+		add(topic)
+	}
+
+	var build func(names []string, depth int) []Topic
+	build = func(names []string, depth int) []Topic {
+		sort.Slice(names, func(i, j int) bool {
+			x, y := registry.DisplayName(names[i], lang), registry.DisplayName(names[j], lang)
+			if x != y {
+				return x < y
+			}
+			return names[i] < names[j]
+		})
+		var tree []Topic
+		for _, name := range names {
+			tree = append(tree, Topic{
+				Key:      name,
+				Name:     registry.DisplayName(name, lang),
+				Depth:    depth,
+				Talks:    topicTalks[name],
+				Children: build(children[name], depth+1),
+			})
+		}
+		return tree
+	}
+	return build(roots, 0)
+}
@@ -0,0 +1,275 @@
+// Package index renders the talks index and summary statistics into the
+// repository READMEs.
+//
+// The sections are rendered from text/template files: the embedded defaults
+// in templates/, each of which the repository can override with a file of
+// the same name in its templates directory. The templates receive a Data
+// value and can call these functions:
+//
+//	msg KEY          the message KEY from the language's catalog
+//	join LIST SEP    strings.Join
+//	rule TEXT        a markdown table separator as wide as " TEXT "
+//	indent DEPTH     two spaces per nesting level
+//	ago DAYS         "updated DAYS days ago" from the language's catalog
+package index
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/template"
+	"unicode/utf8"
+
+	"github.com/shankyjs/talks/internal/config"
+	"github.com/shankyjs/talks/internal/history"
+	"github.com/shankyjs/talks/internal/messages"
+	"github.com/shankyjs/talks/internal/talkrepo"
+)
+
+//go:embed templates/*.tmpl
+var templatesFS embed.FS
+
+// Template names. The index template includes the topics template.
+const (
+	StatsTemplate  = "stats.md.tmpl"
+	IndexTemplate  = "index.md.tmpl"
+	TopicsTemplate = "topics.md.tmpl"
+)
+
+var templateNames = []string{StatsTemplate, IndexTemplate, TopicsTemplate}
+
+// Section markers. Each generated section of a README sits between its
+// start and end comments; anything outside them is left untouched.
+const (
+	StatsStart = "<!-- talks:stats:start -->"
+	StatsEnd   = "<!-- talks:stats:end -->"
+	IndexStart = "<!-- talks:index:start -->"
+	IndexEnd   = "<!-- talks:index:end -->"
+)
+
+// UpdateReadme regenerates the statistics and talks index sections of the
+// README configured for lang. Talks dated before asOf (YYYY-MM-DD) count as
+// past talks.
+func UpdateReadme(root string, cfg *config.Config, lang config.Language, talks []talkrepo.Talk, asOf string) error {
+	_, updated, err := RenderReadme(root, cfg, lang, talks, asOf)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(root, lang.Readme), updated, 0644)
+}
+
+// RenderReadme returns the README configured for lang as it is on disk and
+// with its statistics and talks index sections regenerated, without writing
+// anything. A README without marker comments is migrated first, using the
+// headings configured for lang to find the sections. With index.updated in
+// talks.yaml, the git history of the talks under root is read for the
+// "updated" dates.
+func RenderReadme(root string, cfg *config.Config, lang config.Language, talks []talkrepo.Talk, asOf string) (current, updated []byte, err error) {
+	content, err := os.ReadFile(filepath.Join(root, lang.Readme))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	contentStr := string(content)
+
+	if !strings.Contains(contentStr, IndexStart) && !strings.Contains(contentStr, IndexEnd) {
+		contentStr, err = migrate(contentStr, lang)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	msgs, err := messages.Load(root, cfg.Messages, lang.Code)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tmpl, err := loadTemplates(root, cfg.Templates, msgs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var hist map[string]history.Talk
+	if cfg.Index.Updated {
+		if hist, err = history.Load(root, talks); err != nil {
+			return nil, nil, fmt.Errorf("index.updated: %w", err)
+		}
+	}
+	data := NewData(talks, cfg, lang.Code, asOf, hist)
+
+	// Generate new index
+	newIndex, err := execute(tmpl, IndexTemplate, data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Generate statistics
+	stats, err := execute(tmpl, StatsTemplate, data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// The stats section is optional; the index section is not
+	contentStr, err = replaceSection(contentStr, StatsStart, StatsEnd, stats, false)
+	if err != nil {
+		return nil, nil, err
+	}
+	contentStr, err = replaceSection(contentStr, IndexStart, IndexEnd, newIndex, true)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return content, []byte(contentStr), nil
+}
+
+// findSection returns the positions of the start and end markers of a
+// section, or -1 for both when the README has neither.
+func findSection(content, start, end string) (int, int, error) {
+	starts, ends := strings.Count(content, start), strings.Count(content, end)
+	switch {
+	case starts == 0 && ends == 0:
+		return -1, -1, nil
+	case starts != 1 || ends != 1:
+		return 0, 0, fmt.Errorf("unbalanced markers: found %d %s and %d %s, expected one of each", starts, start, ends, end)
+	}
+
+	i, j := strings.Index(content, start), strings.Index(content, end)
+	if j < i {
+		return 0, 0, fmt.Errorf("unbalanced markers: %s comes before %s", end, start)
+	}
+	return i, j, nil
+}
+
+// replaceSection replaces the text between the start and end markers with
+// body.
+func replaceSection(content, start, end, body string, required bool) (string, error) {
+	i, j, err := findSection(content, start, end)
+	if err != nil {
+		return "", err
+	}
+	if i == -1 {
+		if required {
+			return "", fmt.Errorf("could not find the %s and %s markers", start, end)
+		}
+		return content, nil
+	}
+
+	// Sections must not overlap
+	for _, other := range [][2]string{{StatsStart, StatsEnd}, {IndexStart, IndexEnd}} {
+		if other[0] == start {
+			continue
+		}
+		if strings.Contains(content[i:j], other[0]) {
+			return "", fmt.Errorf("unbalanced markers: %s is inside the %s section", other[0], start)
+		}
+		if strings.Contains(content[i:j], other[1]) {
+			return "", fmt.Errorf("unbalanced markers: %s is inside the %s section", other[1], start)
+		}
+	}
+
+	body = strings.TrimRight(body, "\n")
+	if body != "" {
+		body += "\n"
+	}
+	return content[:i+len(start)] + "\n" + body + content[j:], nil
+}
+
+// migrate adds the marker comments to a README generated before they were
+// introduced: the index ran from the index heading to the end heading, and
+// the stats block was the "## 📊" section right before it.
+func migrate(content string, lang config.Language) (string, error) {
+	if strings.Contains(content, StatsStart) || strings.Contains(content, StatsEnd) {
+		return "", fmt.Errorf("unbalanced markers: found the stats markers but not %s and %s", IndexStart, IndexEnd)
+	}
+
+	// Find index section
+	indexStart := -1
+	if lang.IndexMarker != "" {
+		indexStart = strings.Index(content, lang.IndexMarker)
+	}
+	if indexStart == -1 {
+		return "", fmt.Errorf("could not find the %s and %s markers", IndexStart, IndexEnd)
+	}
+
+	// Find end section
+	endStart := -1
+	if lang.EndMarker != "" {
+		endStart = strings.Index(content[indexStart:], lang.EndMarker)
+	}
+	if endStart == -1 {
+		return "", fmt.Errorf("could not find end section %q to migrate to the %s and %s markers", lang.EndMarker, IndexStart, IndexEnd)
+	}
+	endStart += indexStart
+
+	// Find old stats, which end at the next heading
+	statsMarker := "## 📊"
+	statsStart, statsEnd := indexStart, indexStart
+	if i := strings.Index(content[:indexStart], statsMarker); i != -1 {
+		if j := strings.Index(content[i+len(statsMarker):], "\n## "); j != -1 {
+			statsStart, statsEnd = i, i+len(statsMarker)+j+1
+		}
+	}
+
+	return content[:statsStart] +
+		StatsStart + "\n" + StatsEnd + "\n\n" +
+		content[statsEnd:indexStart] +
+		lang.IndexMarker + "\n\n" +
+		IndexStart + "\n" + IndexEnd + "\n\n" +
+		content[endStart:], nil
+}
+
+// loadTemplates parses the index templates, preferring the files in dir
+// under root over the embedded defaults.
+func loadTemplates(root, dir string, msgs messages.Catalog) (*template.Template, error) {
+	tmpl := template.New("").Funcs(template.FuncMap{
+		"msg":  msgs.Get,
+		"join": strings.Join,
+		"rule": func(text string) string {
+			return strings.Repeat("-", utf8.RuneCountInString(text)+2)
+		},
+		"indent": func(depth int) string {
+			return strings.Repeat("  ", depth)
+		},
+		"ago": func(days int) string {
+			switch days {
+			case 0:
+				return msgs.Get("index.updated_today")
+			case 1:
+				return msgs.Get("index.updated_yesterday")
+			}
+			return strings.ReplaceAll(msgs.Get("index.updated_days"), "{days}", strconv.Itoa(days))
+		},
+	})
+
+	for _, name := range templateNames {
+		file := filepath.ToSlash(filepath.Join(dir, name))
+		content, err := os.ReadFile(filepath.Join(root, dir, name))
+		if errors.Is(err, fs.ErrNotExist) {
+			file = "templates/" + name
+			content, err = templatesFS.ReadFile(file)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if _, err := tmpl.New(name).Parse(string(content)); err != nil {
+			return nil, fmt.Errorf("parsing %s: %w", file, err)
+		}
+	}
+
+	return tmpl, nil
+}
+
+func execute(tmpl *template.Template, name string, data Data) (string, error) {
+	var buf bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&buf, name, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
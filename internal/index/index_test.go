@@ -0,0 +1,66 @@
+package index_test
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/shankyjs/talks/internal/config"
+	"github.com/shankyjs/talks/internal/index"
+	"github.com/shankyjs/talks/internal/talkrepo"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata/golden")
+
+// The README is rendered from the fixture repository of the stats tests, so
+// its statistics block pins the same counts as the stats goldens.
+const (
+	fixture = "../stats/testdata/repo"
+	asOf    = "2025-11-01"
+)
+
+// golden compares got with the golden file name, or rewrites the file
+// with -update.
+func golden(t *testing.T, name string, got []byte) {
+	t.Helper()
+	path := filepath.Join("testdata", "golden", name)
+	if *update {
+		if err := os.WriteFile(path, got, 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%v (run go test ./internal/index -update to create it)", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("output differs from %s (run go test ./internal/index -update to accept it)\n--- got:\n%s\n--- want:\n%s", path, got, want)
+	}
+}
+
+func TestRenderReadme(t *testing.T) {
+	cfg, err := config.Load(fixture)
+	if err != nil {
+		t.Fatal(err)
+	}
+	talks, _, err := talkrepo.Load(fixture)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// As talks index does
+	talkrepo.SortByDateDesc(talks)
+
+	for _, lang := range cfg.Languages {
+		t.Run(lang.Code, func(t *testing.T) {
+			_, got, err := index.RenderReadme(fixture, cfg, lang, talks, asOf)
+			if err != nil {
+				t.Fatal(err)
+			}
+			golden(t, "README."+lang.Code+".md", got)
+		})
+	}
+}
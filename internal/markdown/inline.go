@@ -0,0 +1,312 @@
+package markdown
+
+import (
+	"html"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+var (
+	entityRe   = regexp.MustCompile(`^&(?:[A-Za-z][A-Za-z0-9]*|#[0-9]{1,7}|#[xX][0-9A-Fa-f]{1,6});`)
+	autolinkRe = regexp.MustCompile(`^<([A-Za-z][A-Za-z0-9+.-]{1,31}:[^\s<>]*|[A-Za-z0-9.!#$%&'*+/=?^_{|}~-]+@[A-Za-z0-9.-]+)>`)
+	rawTagRe   = regexp.MustCompile(`^<(?:/?[A-Za-z][A-Za-z0-9-]*(?:\s[^<>]*)?/?|!--[\s\S]*?--)>`)
+	bareURLRe  = regexp.MustCompile(`^https?://[^\s<]*[^\s<.,:;!?'")\]*_~]`)
+)
+
+// inline renders the inline content of a paragraph, heading or table cell.
+func (r *renderer) inline(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == '\\' && i+1 < len(s) && s[i+1] == '\n':
+			b.WriteString("<br>\n")
+			i += 2
+
+		case c == '\\' && i+1 < len(s) && isPunct(s[i+1]):
+			b.WriteString(html.EscapeString(s[i+1 : i+2]))
+			i += 2
+
+		case c == '`':
+			n := run(s, i, '`')
+			if end := closingRun(s, i+n, '`', n); end != -1 {
+				b.WriteString("<code>" + html.EscapeString(codeSpan(s[i+n:end])) + "</code>")
+				i = end + n
+			} else {
+				b.WriteString(s[i : i+n])
+				i += n
+			}
+
+		case c == '!' && strings.HasPrefix(s[i+1:], "["):
+			if text, dest, title, end, ok := parseLink(s, i+1); ok {
+				b.WriteString(r.image(text, dest, title))
+				i = end
+			} else {
+				b.WriteString("!")
+				i++
+			}
+
+		case c == '[':
+			if text, dest, title, end, ok := parseLink(s, i); ok && !r.inLink {
+				b.WriteString(r.link(text, dest, title))
+				i = end
+			} else {
+				b.WriteString("[")
+				i++
+			}
+
+		case c == '<':
+			if m := autolinkRe.FindStringSubmatch(s[i:]); m != nil && !r.inLink {
+				dest := m[1]
+				if !strings.Contains(dest, ":") {
+					dest = "mailto:" + dest
+				}
+				b.WriteString(`<a href="` + html.EscapeString(dest) + `">` + html.EscapeString(m[1]) + "</a>")
+				i += len(m[0])
+			} else if m := rawTagRe.FindString(s[i:]); m != "" {
+				b.WriteString(m)
+				i += len(m)
+			} else {
+				b.WriteString("&lt;")
+				i++
+			}
+
+		case c == '&':
+			if m := entityRe.FindString(s[i:]); m != "" {
+				b.WriteString(m)
+				i += len(m)
+			} else {
+				b.WriteString("&amp;")
+				i++
+			}
+
+		case c == '*' || c == '_' || c == '~':
+			if out, end, ok := r.emphasis(s, i); ok {
+				b.WriteString(out)
+				i = end
+			} else {
+				n := run(s, i, c)
+				b.WriteString(s[i : i+n])
+				i += n
+			}
+
+		case c == 'h' && !r.inLink && (i == 0 || !isWordByte(s[i-1])):
+			if m := bareURLRe.FindString(s[i:]); m != "" {
+				b.WriteString(`<a href="` + html.EscapeString(r.rewrite(m, false)) + `">` + html.EscapeString(m) + "</a>")
+				i += len(m)
+			} else {
+				b.WriteByte(c)
+				i++
+			}
+
+		default:
+			b.WriteString(html.EscapeString(s[i : i+1]))
+			i++
+		}
+	}
+	return b.String()
+}
+
+func isPunct(c byte) bool {
+	return c < utf8.RuneSelf && unicode.IsPunct(rune(c)) || strings.IndexByte("$+<=>^`|~", c) != -1
+}
+
+func isWordByte(c byte) bool {
+	return c >= utf8.RuneSelf || c == '_' || unicode.IsLetter(rune(c)) || unicode.IsDigit(rune(c))
+}
+
+// run returns the length of the run of c starting at i.
+func run(s string, i int, c byte) int {
+	n := 0
+	for i+n < len(s) && s[i+n] == c {
+		n++
+	}
+	return n
+}
+
+// closingRun returns the index of the next run of exactly n c characters
+// from i, or -1.
+func closingRun(s string, i int, c byte, n int) int {
+	for i < len(s) {
+		j := strings.IndexByte(s[i:], c)
+		if j == -1 {
+			return -1
+		}
+		j += i
+		m := run(s, j, c)
+		if m == n {
+			return j
+		}
+		i = j + m
+	}
+	return -1
+}
+
+// codeSpan normalizes the content of a code span: line endings become
+// spaces and one space padding both ends is removed.
+func codeSpan(s string) string {
+	s = strings.ReplaceAll(s, "\n", " ")
+	if len(s) >= 2 && s[0] == ' ' && s[len(s)-1] == ' ' && strings.Trim(s, " ") != "" {
+		s = s[1 : len(s)-1]
+	}
+	return s
+}
+
+// emphasis renders the emphasis, strong emphasis or strikethrough opening
+// at i, returning the HTML and the index after its closing delimiter.
+func (r *renderer) emphasis(s string, i int) (string, int, bool) {
+	c := s[i]
+	n := run(s, i, c)
+	after := i + n
+	if after >= len(s) || s[after] == ' ' || s[after] == '\n' {
+		return "", 0, false
+	}
+	// Underscores inside words, like snake_case, are not emphasis
+	if c == '_' && i > 0 && isWordByte(s[i-1]) {
+		return "", 0, false
+	}
+
+	var tag string
+	switch {
+	case c == '~' && n == 2:
+		tag = "del"
+	case c == '~':
+		return "", 0, false
+	case n == 1:
+		tag = "em"
+	case n == 2:
+		tag = "strong"
+	default:
+		n = 3
+	}
+
+	for j := after; j < len(s); {
+		k := strings.IndexByte(s[j:], c)
+		if k == -1 {
+			break
+		}
+		k += j
+		m := run(s, k, c)
+		closes := m == n && s[k-1] != ' ' && s[k-1] != '\n' &&
+			(c != '_' || k+m == len(s) || !isWordByte(s[k+m]))
+		if closes {
+			inner := r.inline(s[after:k])
+			if n == 3 {
+				return "<em><strong>" + inner + "</strong></em>", k + m, true
+			}
+			return "<" + tag + ">" + inner + "</" + tag + ">", k + m, true
+		}
+		j = k + m
+	}
+	return "", 0, false
+}
+
+// parseLink parses an inline link [text](dest "title") starting at the
+// opening bracket at i, returning the index after it.
+func parseLink(s string, i int) (text, dest, title string, end int, ok bool) {
+	depth := 0
+	j := i
+	for ; j < len(s); j++ {
+		switch s[j] {
+		case '\\':
+			j++
+		case '`':
+			n := run(s, j, '`')
+			if k := closingRun(s, j+n, '`', n); k != -1 {
+				j = k + n - 1
+			} else {
+				j += n - 1
+			}
+		case '[':
+			depth++
+		case ']':
+			depth--
+		}
+		if depth == 0 {
+			break
+		}
+	}
+	if j >= len(s) || j+1 >= len(s) || s[j+1] != '(' {
+		return "", "", "", 0, false
+	}
+	text = s[i+1 : j]
+
+	k := j + 2
+	for k < len(s) && (s[k] == ' ' || s[k] == '\n') {
+		k++
+	}
+	if k < len(s) && s[k] == '<' {
+		close := strings.IndexByte(s[k:], '>')
+		if close == -1 {
+			return "", "", "", 0, false
+		}
+		dest = s[k+1 : k+close]
+		k += close + 1
+	} else {
+		start, parens := k, 0
+		for ; k < len(s) && s[k] != ' ' && s[k] != '\n'; k++ {
+			if s[k] == '(' {
+				parens++
+			} else if s[k] == ')' {
+				if parens == 0 {
+					break
+				}
+				parens--
+			}
+		}
+		dest = s[start:k]
+	}
+
+	for k < len(s) && (s[k] == ' ' || s[k] == '\n') {
+		k++
+	}
+	if k < len(s) && (s[k] == '"' || s[k] == '\'') {
+		close := strings.IndexByte(s[k+1:], s[k])
+		if close == -1 {
+			return "", "", "", 0, false
+		}
+		title = s[k+1 : k+1+close]
+		k += close + 2
+		for k < len(s) && (s[k] == ' ' || s[k] == '\n') {
+			k++
+		}
+	}
+	if k >= len(s) || s[k] != ')' {
+		return "", "", "", 0, false
+	}
+	return text, dest, title, k + 1, true
+}
+
+func (r *renderer) rewrite(dest string, image bool) string {
+	if r.opts.Link == nil {
+		return dest
+	}
+	return r.opts.Link(dest, image)
+}
+
+func (r *renderer) link(text, dest, title string) string {
+	r.inLink = true
+	content := r.inline(text)
+	r.inLink = false
+
+	out := `<a href="` + html.EscapeString(r.rewrite(dest, false)) + `"`
+	if title != "" {
+		out += ` title="` + html.EscapeString(title) + `"`
+	}
+	return out + ">" + content + "</a>"
+}
+
+func (r *renderer) image(alt, dest, title string) string {
+	inLink := r.inLink
+	r.inLink = true
+	alt = plainText(r.inline(alt))
+	r.inLink = inLink
+
+	out := `<img src="` + html.EscapeString(r.rewrite(dest, true)) + `" alt="` + html.EscapeString(alt) + `"`
+	if title != "" {
+		out += ` title="` + html.EscapeString(title) + `"`
+	}
+	return out + ">"
+}
@@ -0,0 +1,540 @@
+// Package markdown renders the talk READMEs to HTML for the site.
+//
+// It covers the subset of GitHub Flavored Markdown the READMEs use: ATX and
+// setext headings, paragraphs, bullet, ordered and task lists, fenced and
+// indented code blocks, block quotes, tables, thematic breaks and raw HTML
+// blocks, and inline code, emphasis, strikethrough, links, images,
+// autolinks and raw HTML. It is not a complete CommonMark implementation;
+// it exists so the site builds without tools from outside this repository.
+package markdown
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// Options configure rendering.
+type Options struct {
+	// Link rewrites the destination of every link and image; image is true
+	// for images. Destinations are used as written when it is nil.
+	Link func(dest string, image bool) string
+}
+
+// ToHTML renders src as HTML. Headings get GitHub-style ids, so links to
+// README sections keep working.
+func ToHTML(src []byte, opts Options) string {
+	r := &renderer{opts: opts, ids: make(map[string]int)}
+	var b strings.Builder
+	r.blocks(&b, splitLines(string(src)))
+	return b.String()
+}
+
+type renderer struct {
+	opts   Options
+	ids    map[string]int // heading ids in use, with the number of times
+	tight  bool           // rendering a tight list item: no <p> tags
+	inLink bool           // rendering link text: no nested links
+}
+
+func splitLines(s string) []string {
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	s = strings.ReplaceAll(s, "\t", "    ")
+	s = strings.TrimSuffix(s, "\n")
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, "\n")
+}
+
+func isBlank(line string) bool {
+	return strings.TrimSpace(line) == ""
+}
+
+// indent returns the number of leading spaces of line.
+func indent(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " "))
+}
+
+// dedent removes up to n leading spaces from line.
+func dedent(line string, n int) string {
+	return line[min(n, indent(line)):]
+}
+
+var (
+	headingRe   = regexp.MustCompile(`^ {0,3}(#{1,6})(?:[ ]+(.*?))?(?:[ ]+#+)?[ ]*$`)
+	ruleRe      = regexp.MustCompile(`^ {0,3}(?:(?:\*[ ]*){3,}|(?:-[ ]*){3,}|(?:_[ ]*){3,})$`)
+	setextRe    = regexp.MustCompile(`^ {0,3}(=+|-+)[ ]*$`)
+	bulletRe    = regexp.MustCompile(`^( {0,3})([-*+])( +|$)`)
+	orderedRe   = regexp.MustCompile(`^( {0,3})(\d{1,9})([.)])( +|$)`)
+	delimRowRe  = regexp.MustCompile(`^ {0,3}\|?[ ]*:?-+:?[ ]*(\|[ ]*:?-+:?[ ]*)*\|?[ ]*$`)
+	htmlBlockRe = regexp.MustCompile(`^ {0,3}<(?:[A-Za-z][A-Za-z0-9-]*(?:[ />]|$)|/[A-Za-z]|!--)`)
+	taskRe      = regexp.MustCompile(`^\[([ xX])\][ ]+`)
+)
+
+// blocks renders the block-level content of lines.
+func (r *renderer) blocks(b *strings.Builder, lines []string) {
+	for i := 0; i < len(lines); {
+		line := lines[i]
+		switch {
+		case isBlank(line):
+			i++
+		case fence(line) != "":
+			i = r.fencedCode(b, lines, i)
+		case indent(line) >= 4:
+			i = r.indentedCode(b, lines, i)
+		case headingRe.MatchString(line):
+			m := headingRe.FindStringSubmatch(line)
+			r.heading(b, len(m[1]), m[2])
+			i++
+		case ruleRe.MatchString(line):
+			b.WriteString("<hr>\n")
+			i++
+		case isQuote(line):
+			i = r.quote(b, lines, i)
+		case isListItem(line):
+			i = r.list(b, lines, i)
+		case isTableStart(lines, i):
+			i = r.table(b, lines, i)
+		case htmlBlockRe.MatchString(line):
+			i = r.htmlBlock(b, lines, i)
+		default:
+			i = r.paragraph(b, lines, i)
+		}
+	}
+}
+
+// interrupts reports whether line starts a block that ends a paragraph.
+func interrupts(line string) bool {
+	return fence(line) != "" || headingRe.MatchString(line) || ruleRe.MatchString(line) ||
+		isQuote(line) || isListItem(line) || htmlBlockRe.MatchString(line)
+}
+
+func (r *renderer) heading(b *strings.Builder, level int, text string) {
+	content := r.inline(strings.TrimSpace(text))
+	fmt.Fprintf(b, "<h%d id=\"%s\">%s</h%d>\n", level, r.headingID(content), content, level)
+}
+
+// headingID returns the GitHub-style anchor of a rendered heading: its
+// lowercased text without punctuation, with spaces turned into hyphens and
+// a numeric suffix for repeated headings.
+func (r *renderer) headingID(content string) string {
+	var id strings.Builder
+	for _, c := range strings.ToLower(plainText(content)) {
+		switch {
+		case unicode.IsLetter(c) || unicode.IsDigit(c) || c == '_' || c == '-':
+			id.WriteRune(c)
+		case c == ' ':
+			id.WriteByte('-')
+		}
+	}
+
+	base := id.String()
+	n := r.ids[base]
+	r.ids[base]++
+	if n > 0 {
+		return fmt.Sprintf("%s-%d", base, n)
+	}
+	return base
+}
+
+var tagRe = regexp.MustCompile(`<[^>]*>`)
+
+// plainText strips the tags and entities of rendered HTML.
+func plainText(s string) string {
+	return html.UnescapeString(tagRe.ReplaceAllString(s, ""))
+}
+
+// fence returns the opening code fence of line (e.g. "```"), or "".
+func fence(line string) string {
+	if indent(line) > 3 {
+		return ""
+	}
+	s := strings.TrimLeft(line, " ")
+	if s == "" || (s[0] != '`' && s[0] != '~') {
+		return ""
+	}
+	n := len(s) - len(strings.TrimLeft(s, s[:1]))
+	if n < 3 || (s[0] == '`' && strings.Contains(s[n:], "`")) {
+		return ""
+	}
+	return s[:n]
+}
+
+func (r *renderer) fencedCode(b *strings.Builder, lines []string, i int) int {
+	open := fence(lines[i])
+	ind := indent(lines[i])
+	info := strings.Fields(strings.TrimLeft(lines[i], " ")[len(open):])
+
+	var code []string
+	j := i + 1
+	for ; j < len(lines); j++ {
+		s := strings.TrimSpace(lines[j])
+		if indent(lines[j]) <= 3 && strings.HasPrefix(s, open) && strings.Trim(s, open[:1]) == "" {
+			j++
+			break
+		}
+		code = append(code, dedent(lines[j], ind))
+	}
+
+	writeCode(b, code, info)
+	return j
+}
+
+func (r *renderer) indentedCode(b *strings.Builder, lines []string, i int) int {
+	var code []string
+	j := i
+	for ; j < len(lines); j++ {
+		if !isBlank(lines[j]) && indent(lines[j]) < 4 {
+			break
+		}
+		code = append(code, dedent(lines[j], 4))
+	}
+	for len(code) > 0 && isBlank(code[len(code)-1]) {
+		code = code[:len(code)-1]
+	}
+
+	writeCode(b, code, nil)
+	return j
+}
+
+func writeCode(b *strings.Builder, code, info []string) {
+	b.WriteString("<pre><code")
+	if len(info) > 0 {
+		fmt.Fprintf(b, " class=\"language-%s\"", html.EscapeString(info[0]))
+	}
+	b.WriteString(">")
+	for _, line := range code {
+		b.WriteString(html.EscapeString(line))
+		b.WriteString("\n")
+	}
+	b.WriteString("</code></pre>\n")
+}
+
+func isQuote(line string) bool {
+	return indent(line) <= 3 && strings.HasPrefix(strings.TrimLeft(line, " "), ">")
+}
+
+func (r *renderer) quote(b *strings.Builder, lines []string, i int) int {
+	var inner []string
+	j := i
+	for ; j < len(lines); j++ {
+		line := lines[j]
+		if isQuote(line) {
+			line = strings.TrimPrefix(strings.TrimLeft(line, " ")[1:], " ")
+		} else if isBlank(line) || interrupts(line) || isBlank(inner[len(inner)-1]) {
+			break
+		}
+		// Otherwise a lazy continuation of the quoted paragraph
+		inner = append(inner, line)
+	}
+
+	b.WriteString("<blockquote>\n")
+	tight := r.tight
+	r.tight = false
+	r.blocks(b, inner)
+	r.tight = tight
+	b.WriteString("</blockquote>\n")
+	return j
+}
+
+// listMarker describes the marker of a list item line.
+type listMarker struct {
+	ordered bool
+	start   string // number of an ordered item
+	delim   string // bullet character, or "." or ")" after the number
+	width   int    // indentation of the item content
+}
+
+func parseMarker(line string) (listMarker, bool) {
+	var m listMarker
+	var spaces string
+	if sub := bulletRe.FindStringSubmatch(line); sub != nil {
+		m.delim, spaces = sub[2], sub[3]
+		m.width = len(sub[1]) + 1
+	} else if sub := orderedRe.FindStringSubmatch(line); sub != nil {
+		m.ordered, m.start, m.delim, spaces = true, sub[2], sub[3], sub[4]
+		m.width = len(sub[1]) + len(sub[2]) + 1
+	} else {
+		return m, false
+	}
+
+	// Content indented more than four spaces past the marker is an
+	// indented code block, so only one of those spaces belongs to the marker
+	switch n := len(spaces); {
+	case n == 0 || isBlank(line[m.width:]):
+		m.width++
+	case n > 4:
+		m.width++
+	default:
+		m.width += n
+	}
+	return m, true
+}
+
+func isListItem(line string) bool {
+	_, ok := parseMarker(line)
+	return ok
+}
+
+func (r *renderer) list(b *strings.Builder, lines []string, i int) int {
+	first, _ := parseMarker(lines[i])
+
+	var items [][]string
+	loose := false
+	j := i
+	for j < len(lines) {
+		m, ok := parseMarker(lines[j])
+		if !ok || m.ordered != first.ordered || m.delim != first.delim {
+			break
+		}
+
+		item := []string{lines[j][min(m.width, len(lines[j])):]}
+		for j++; j < len(lines); j++ {
+			line := lines[j]
+			if isBlank(line) {
+				k := j
+				for k < len(lines) && isBlank(lines[k]) {
+					k++
+				}
+				if k == len(lines) || indent(lines[k]) < m.width {
+					break
+				}
+				item = append(item, "")
+				continue
+			}
+			if indent(line) >= m.width {
+				item = append(item, dedent(line, m.width))
+				continue
+			}
+			if interrupts(line) || isBlank(item[len(item)-1]) || inFence(item) {
+				break
+			}
+			// A lazy continuation of the item's last paragraph
+			item = append(item, strings.TrimLeft(line, " "))
+		}
+		if hasBlankBetweenBlocks(item) {
+			loose = true
+		}
+		items = append(items, item)
+
+		// Blank lines between items make the list loose
+		k := j
+		for k < len(lines) && isBlank(lines[k]) {
+			k++
+		}
+		if k < len(lines) && k > j {
+			if next, ok := parseMarker(lines[k]); ok && next.ordered == first.ordered && next.delim == first.delim {
+				loose = true
+			}
+		}
+		j = k
+		if j < len(lines) && !isListItem(lines[j]) {
+			break
+		}
+	}
+
+	tag := "ul"
+	if first.ordered {
+		tag = "ol"
+	}
+	b.WriteString("<" + tag)
+	if first.ordered && strings.TrimLeft(first.start, "0") != "1" {
+		fmt.Fprintf(b, " start=\"%s\"", strings.TrimLeft(first.start, "0"))
+	}
+	b.WriteString(">\n")
+
+	tight := r.tight
+	r.tight = !loose
+	for _, item := range items {
+		b.WriteString("<li>")
+		if m := taskRe.FindStringSubmatch(item[0]); m != nil {
+			checked := ""
+			if m[1] != " " {
+				checked = " checked"
+			}
+			fmt.Fprintf(b, "<input type=\"checkbox\" disabled%s> ", checked)
+			item[0] = item[0][len(m[0]):]
+		}
+		var inner strings.Builder
+		r.blocks(&inner, item)
+		content := inner.String()
+		if r.tight {
+			content = strings.TrimSuffix(content, "\n")
+		} else if content != "" {
+			b.WriteString("\n")
+		}
+		b.WriteString(content)
+		b.WriteString("</li>\n")
+	}
+	r.tight = tight
+
+	b.WriteString("</" + tag + ">\n")
+	return j
+}
+
+// inFence reports whether lines end inside an unclosed code fence.
+func inFence(lines []string) bool {
+	open := ""
+	for _, line := range lines {
+		s := strings.TrimSpace(line)
+		switch {
+		case open == "":
+			open = fence(line)
+		case strings.HasPrefix(s, open) && strings.Trim(s, open[:1]) == "":
+			open = ""
+		}
+	}
+	return open != ""
+}
+
+// hasBlankBetweenBlocks reports whether an item has a blank line between
+// two of its blocks (outside code fences), which makes its list loose.
+func hasBlankBetweenBlocks(lines []string) bool {
+	for n := len(lines); n > 0 && isBlank(lines[n-1]); n-- {
+		lines = lines[:n-1]
+	}
+	for i, line := range lines {
+		if isBlank(line) && !inFence(lines[:i]) {
+			// Blank lines inside a nested list belong to that list
+			return indent(lines[i+1]) == 0 && !isListItem(lines[i+1])
+		}
+	}
+	return false
+}
+
+func isTableStart(lines []string, i int) bool {
+	if i+1 >= len(lines) || !strings.Contains(lines[i], "|") || !delimRowRe.MatchString(lines[i+1]) {
+		return false
+	}
+	return len(splitRow(lines[i])) == len(splitRow(lines[i+1]))
+}
+
+// splitRow splits a table row into its trimmed cells. Pipes escaped with a
+// backslash or inside code spans do not separate cells.
+func splitRow(line string) []string {
+	s := strings.TrimSpace(line)
+	s = strings.TrimPrefix(s, "|")
+	if strings.HasSuffix(s, "|") && !strings.HasSuffix(s, `\|`) {
+		s = s[:len(s)-1]
+	}
+
+	var cells []string
+	var cell strings.Builder
+	inCode := false
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '\\' && i+1 < len(s) && s[i+1] == '|':
+			cell.WriteByte('|')
+			i++
+		case c == '`':
+			inCode = !inCode
+			cell.WriteByte(c)
+		case c == '|' && !inCode:
+			cells = append(cells, strings.TrimSpace(cell.String()))
+			cell.Reset()
+		default:
+			cell.WriteByte(c)
+		}
+	}
+	return append(cells, strings.TrimSpace(cell.String()))
+}
+
+func (r *renderer) table(b *strings.Builder, lines []string, i int) int {
+	header := splitRow(lines[i])
+	var aligns []string
+	for _, d := range splitRow(lines[i+1]) {
+		switch {
+		case strings.HasPrefix(d, ":") && strings.HasSuffix(d, ":"):
+			aligns = append(aligns, "center")
+		case strings.HasSuffix(d, ":"):
+			aligns = append(aligns, "right")
+		case strings.HasPrefix(d, ":"):
+			aligns = append(aligns, "left")
+		default:
+			aligns = append(aligns, "")
+		}
+	}
+
+	row := func(tag string, cells []string) {
+		b.WriteString("<tr>\n")
+		for c := range header {
+			b.WriteString("<" + tag)
+			if aligns[c] != "" {
+				fmt.Fprintf(b, " align=\"%s\"", aligns[c])
+			}
+			b.WriteString(">")
+			if c < len(cells) {
+				b.WriteString(r.inline(cells[c]))
+			}
+			b.WriteString("</" + tag + ">\n")
+		}
+		b.WriteString("</tr>\n")
+	}
+
+	b.WriteString("<table>\n<thead>\n")
+	row("th", header)
+	b.WriteString("</thead>\n")
+
+	j := i + 2
+	if j < len(lines) && !isBlank(lines[j]) && !interrupts(lines[j]) {
+		b.WriteString("<tbody>\n")
+		for ; j < len(lines) && !isBlank(lines[j]) && !interrupts(lines[j]); j++ {
+			row("td", splitRow(lines[j]))
+		}
+		b.WriteString("</tbody>\n")
+	}
+	b.WriteString("</table>\n")
+	return j
+}
+
+// htmlBlock copies raw HTML up to the next blank line.
+func (r *renderer) htmlBlock(b *strings.Builder, lines []string, i int) int {
+	j := i
+	for ; j < len(lines) && !isBlank(lines[j]); j++ {
+		b.WriteString(lines[j])
+		b.WriteString("\n")
+	}
+	return j
+}
+
+func (r *renderer) paragraph(b *strings.Builder, lines []string, i int) int {
+	var text []string
+	j := i
+	for ; j < len(lines); j++ {
+		line := lines[j]
+		if isBlank(line) {
+			break
+		}
+		if j > i {
+			if m := setextRe.FindStringSubmatch(line); m != nil {
+				level := 1
+				if m[1][0] == '-' {
+					level = 2
+				}
+				r.heading(b, level, strings.Join(text, "\n"))
+				return j + 1
+			}
+			if interrupts(line) {
+				break
+			}
+		}
+
+		line = strings.TrimLeft(line, " ")
+		// Two trailing spaces are a hard line break, like a backslash
+		if strings.HasSuffix(line, "  ") {
+			line = strings.TrimRight(line, " ") + `\`
+		}
+		text = append(text, line)
+	}
+
+	content := r.inline(strings.TrimSuffix(strings.TrimRight(strings.Join(text, "\n"), " "), `\`))
+	if r.tight {
+		b.WriteString(content + "\n")
+	} else {
+		b.WriteString("<p>" + content + "</p>\n")
+	}
+	return j
+}
@@ -0,0 +1,215 @@
+package markdown_test
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/shankyjs/talks/internal/markdown"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+func TestToHTML(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		src  string
+		want string
+	}{
+		{
+			name: "atx headings",
+			src:  "# Title\n\n## Sub *section* `code`\n\n### Title\n\n#### Title",
+			want: `<h1 id="title">Title</h1>
+<h2 id="sub-section-code">Sub <em>section</em> <code>code</code></h2>
+<h3 id="title-1">Title</h3>
+<h4 id="title-2">Title</h4>
+`,
+		},
+		{
+			name: "setext headings",
+			src:  "Setext\n======\n\nSub\n---",
+			want: `<h1 id="setext">Setext</h1>
+<h2 id="sub">Sub</h2>
+`,
+		},
+		{
+			name: "nested lists",
+			src:  "- one\n- two\n  - nested\n  - nested two\n    1. deep\n- three",
+			want: `<ul>
+<li>one</li>
+<li>two
+<ul>
+<li>nested</li>
+<li>nested two
+<ol>
+<li>deep</li>
+</ol></li>
+</ul></li>
+<li>three</li>
+</ul>
+`,
+		},
+		{
+			name: "loose ordered list",
+			src:  "1. first\n2. second\n\n   para\n3. third",
+			want: `<ol>
+<li>
+<p>first</p>
+</li>
+<li>
+<p>second</p>
+<p>para</p>
+</li>
+<li>
+<p>third</p>
+</li>
+</ol>
+`,
+		},
+		{
+			name: "task list",
+			src:  "- [x] done\n- [ ] todo",
+			want: `<ul>
+<li><input type="checkbox" disabled checked> done</li>
+<li><input type="checkbox" disabled> todo</li>
+</ul>
+`,
+		},
+		{
+			name: "fenced code with language",
+			src:  "```go\nfunc main() {\n    fmt.Println(\"<hi>\")\n}\n```",
+			want: `<pre><code class="language-go">func main() {
+    fmt.Println(&#34;&lt;hi&gt;&#34;)
+}
+</code></pre>
+`,
+		},
+		{
+			name: "tilde fence",
+			src:  "~~~\nplain & **raw**\n~~~",
+			want: "<pre><code>plain &amp; **raw**\n</code></pre>\n",
+		},
+		{
+			name: "indented code",
+			src:  "    indented\n    code",
+			want: "<pre><code>indented\ncode\n</code></pre>\n",
+		},
+		{
+			name: "table with alignment and escaped pipe",
+			src:  "| Name | Count |\n|:-----|------:|\n| `a` | 1 |\n| b \\| c | 2 |",
+			want: `<table>
+<thead>
+<tr>
+<th align="left">Name</th>
+<th align="right">Count</th>
+</tr>
+</thead>
+<tbody>
+<tr>
+<td align="left"><code>a</code></td>
+<td align="right">1</td>
+</tr>
+<tr>
+<td align="left">b | c</td>
+<td align="right">2</td>
+</tr>
+</tbody>
+</table>
+`,
+		},
+		{
+			name: "links and images",
+			src:  "[link](https://example.com \"Title\") and ![alt](img/a.png)",
+			want: `<p><a href="https://example.com" title="Title">link</a> and <img src="img/a.png" alt="alt"></p>
+`,
+		},
+		{
+			name: "autolink and relative link",
+			src:  "<https://example.com> and [ref](docs/README.md#setup)",
+			want: `<p><a href="https://example.com">https://example.com</a> and <a href="docs/README.md#setup">ref</a></p>
+`,
+		},
+		{
+			name: "emphasis and inline code",
+			src:  "*em* **strong** ***both*** ~~gone~~ `a < b` snake_case_name",
+			want: `<p><em>em</em> <strong>strong</strong> <em><strong>both</strong></em> <del>gone</del> <code>a &lt; b</code> snake_case_name</p>
+`,
+		},
+		{
+			name: "escaped text",
+			src:  "a & b < c",
+			want: "<p>a &amp; b &lt; c</p>\n",
+		},
+		{
+			name: "raw html block",
+			src:  "<details>\n<summary>More</summary>\n\nHidden *text*\n\n</details>",
+			want: `<details>
+<summary>More</summary>
+<p>Hidden <em>text</em></p>
+</details>
+`,
+		},
+		{
+			name: "raw inline html",
+			src:  "Press <kbd>Ctrl</kbd> now",
+			want: "<p>Press <kbd>Ctrl</kbd> now</p>\n",
+		},
+		{
+			name: "block quote",
+			src:  "> quote\n> more",
+			want: "<blockquote>\n<p>quote\nmore</p>\n</blockquote>\n",
+		},
+		{
+			name: "thematic break",
+			src:  "---",
+			want: "<hr>\n",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := markdown.ToHTML([]byte(tt.src), markdown.Options{}); got != tt.want {
+				t.Errorf("ToHTML(%q)\n--- got:\n%s\n--- want:\n%s", tt.src, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLinkOption(t *testing.T) {
+	src := "[a](docs/x.md) ![b](img.png)"
+	got := markdown.ToHTML([]byte(src), markdown.Options{Link: func(dest string, image bool) string {
+		if image {
+			return "/raw/" + dest
+		}
+		return "/blob/" + dest
+	}})
+	want := `<p><a href="/blob/docs/x.md">a</a> <img src="/raw/img.png" alt="b"></p>` + "\n"
+	if got != want {
+		t.Errorf("ToHTML(%q) = %q, want %q", src, got, want)
+	}
+}
+
+// TestReadme renders a copy of a talk README from this repository, so a
+// change to any construct the READMEs use shows up in the golden file.
+func TestReadme(t *testing.T) {
+	src, err := os.ReadFile(filepath.Join("testdata", "README.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := markdown.ToHTML(src, markdown.Options{})
+
+	path := filepath.Join("testdata", "README.html")
+	if *update {
+		if err := os.WriteFile(path, []byte(got), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%v (run go test ./internal/markdown -update to create it)", err)
+	}
+	if got != string(want) {
+		t.Errorf("output differs from %s (run go test ./internal/markdown -update to accept it)\n--- got:\n%s\n--- want:\n%s", path, got, want)
+	}
+}
@@ -0,0 +1,84 @@
+// Package messages holds the per-language message catalogs used to render
+// the generated README sections and the site.
+//
+// Catalogs for English and Spanish are embedded. A repository can override
+// any message, or add a language, with a <code>.yaml file in its messages
+// directory (i18n/ by default). Messages missing from a catalog fall back to
+// English.
+package messages
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Fallback is the language whose embedded messages fill in missing ones.
+const Fallback = "en"
+
+//go:embed catalogs/*.yaml
+var catalogs embed.FS
+
+// Catalog maps message keys (e.g. "stats.heading") to their text.
+type Catalog map[string]string
+
+// Get returns the message for key, or key itself when it is missing.
+func (c Catalog) Get(key string) string {
+	if msg, ok := c[key]; ok {
+		return msg
+	}
+	return key
+}
+
+// Load returns the catalog for lang: the embedded English messages,
+// overlaid with the embedded catalog for lang and then with dir/<lang>.yaml
+// under root. It fails when lang has neither an embedded nor a repository
+// catalog.
+func Load(root, dir, lang string) (Catalog, error) {
+	c := make(Catalog)
+	if _, err := c.overlay(catalogs.ReadFile, "catalogs/"+Fallback+".yaml"); err != nil {
+		return nil, err
+	}
+
+	embedded, err := c.overlay(catalogs.ReadFile, "catalogs/"+lang+".yaml")
+	if err != nil {
+		return nil, err
+	}
+
+	path := filepath.Join(root, dir, lang+".yaml")
+	local, err := c.overlay(os.ReadFile, path)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filepath.ToSlash(filepath.Join(dir, lang+".yaml")), err)
+	}
+
+	if !embedded && !local {
+		return nil, fmt.Errorf("no message catalog for language %q (add %s)", lang, filepath.ToSlash(filepath.Join(dir, lang+".yaml")))
+	}
+	return c, nil
+}
+
+// overlay reads the catalog file name with read and merges it into c. It
+// reports whether the file exists.
+func (c Catalog) overlay(read func(string) ([]byte, error), name string) (bool, error) {
+	data, err := read(name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	var messages map[string]string
+	if err := yaml.Unmarshal(data, &messages); err != nil {
+		return false, err
+	}
+	for key, msg := range messages {
+		c[key] = msg
+	}
+	return true, nil
+}
@@ -0,0 +1,148 @@
+// Package scaffold creates new talk directories from the embedded templates.
+package scaffold
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/shankyjs/talks/internal/config"
+	"github.com/shankyjs/talks/internal/talkrepo"
+)
+
+//go:embed templates/*
+var templatesFS embed.FS
+
+// TalkData is the data passed to the talk templates.
+type TalkData struct {
+	Title       string
+	Date        string
+	Event       string
+	Description string
+	Topics      []string
+	Slug        string
+}
+
+// Create scaffolds a new talk directory under root, filling in the
+// placeholders of cfg, and returns its path relative to root (e.g.
+// 2025/nov-15th-kubernetes-scaling) with the files created in it: the
+// metadata and the talk README of every configured language that has a
+// template (see HasTemplate).
+func Create(root string, cfg *config.Config, dateStr, slug, title string) (string, []string, error) {
+	// Parse date
+	date, err := time.Parse("2006-01-02", dateStr)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid date format '%s'. Use YYYY-MM-DD", dateStr)
+	}
+
+	// Generate title from slug if not provided
+	if title == "" {
+		title = strings.Title(strings.ReplaceAll(strings.ReplaceAll(slug, "-", " "), "_", " "))
+	}
+
+	// Create directory name
+	talkPath := filepath.Join(date.Format("2006"), fmt.Sprintf("%s-%s", FormatMonthDay(date), slug))
+	fullPath := filepath.Join(root, talkPath)
+
+	// Check if directory exists
+	if _, err := os.Stat(fullPath); err == nil {
+		return "", nil, fmt.Errorf("directory already exists: %s", talkPath)
+	}
+
+	// Create directory
+	if err := os.MkdirAll(fullPath, 0755); err != nil {
+		return "", nil, fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	// Prepare template data
+	data := TalkData{
+		Title:       title,
+		Date:        dateStr,
+		Event:       cfg.Placeholders.Event(),
+		Description: cfg.Placeholders.Description,
+		Topics:      cfg.Placeholders.Topics,
+		Slug:        slug,
+	}
+
+	// Create files from templates
+	files := []string{talkrepo.MetadataFile}
+	for _, lang := range cfg.Languages {
+		if HasTemplate(lang.TalkReadme) {
+			files = append(files, lang.TalkReadme)
+		}
+	}
+	for _, file := range files {
+		if err := renderTemplate(file+".tmpl", filepath.Join(fullPath, file), data); err != nil {
+			return "", nil, fmt.Errorf("failed to create %s: %w", file, err)
+		}
+	}
+
+	return talkPath, files, nil
+}
+
+// FormatMonthDay returns the directory prefix used for a talk on the given
+// date, e.g. "feb-25th".
+func FormatMonthDay(date time.Time) string {
+	month := strings.ToLower(date.Format("Jan"))
+	day := date.Day()
+
+	suffix := "th"
+	if day%100 < 11 || day%100 > 13 {
+		switch day % 10 {
+		case 1:
+			suffix = "st"
+		case 2:
+			suffix = "nd"
+		case 3:
+			suffix = "rd"
+		}
+	}
+
+	return fmt.Sprintf("%s-%d%s", month, day, suffix)
+}
+
+// HasTemplate reports whether there is a template for the named talk file
+// (e.g. "README-es.md").
+func HasTemplate(name string) bool {
+	_, err := templatesFS.ReadFile("templates/" + name + ".tmpl")
+	return err == nil
+}
+
+// Render renders the template for the named talk file (e.g. "README-es.md").
+func Render(name string, data TalkData) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := executeTemplate(name+".tmpl", &buf, data); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func renderTemplate(tmplFile, outputFile string, data TalkData) error {
+	f, err := os.Create(outputFile)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return executeTemplate(tmplFile, f, data)
+}
+
+func executeTemplate(tmplFile string, w io.Writer, data TalkData) error {
+	tmplContent, err := templatesFS.ReadFile("templates/" + tmplFile)
+	if err != nil {
+		return err
+	}
+
+	tmpl, err := template.New(tmplFile).Parse(string(tmplContent))
+	if err != nil {
+		return err
+	}
+
+	return tmpl.Execute(w, data)
+}
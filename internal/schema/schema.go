@@ -0,0 +1,365 @@
+// Package schema holds the JSON Schema for metadata.yaml and validates
+// metadata files against it.
+//
+// The validator implements the subset of JSON Schema used by the shipped
+// schema: type (a name or a list of names), required, properties,
+// additionalProperties, minProperties, items, minLength, minItems and
+// pattern. Other keywords (title, description, format) are annotations for
+// editors and are not enforced.
+package schema
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/shankyjs/talks/internal/config"
+)
+
+// Version is the version of the metadata schema; it is part of the schema $id.
+const Version = "v1"
+
+//go:embed metadata.schema.json
+var metadataSchema []byte
+
+// Schema is a (subset of a) JSON Schema document.
+type Schema struct {
+	Schema      string     `json:"$schema,omitempty"`
+	ID          string     `json:"$id,omitempty"`
+	Title       string     `json:"title,omitempty"`
+	Description string     `json:"description,omitempty"`
+	Type        Types      `json:"type,omitempty"`
+	Format      string     `json:"format,omitempty"`
+	Pattern     string     `json:"pattern,omitempty"`
+	MinLength   *int       `json:"minLength,omitempty"`
+	MinItems    *int       `json:"minItems,omitempty"`
+	Required    []string   `json:"required,omitempty"`
+	Properties  Properties `json:"properties,omitempty"`
+	MinProps    *int       `json:"minProperties,omitempty"`
+	Additional  *Schema    `json:"additionalProperties,omitempty"`
+	Items       *Schema    `json:"items,omitempty"`
+
+	pattern *regexp.Regexp
+}
+
+// Types are the allowed JSON types of a value. It is written as a single
+// name when there is only one.
+type Types []string
+
+func (t Types) MarshalJSON() ([]byte, error) {
+	if len(t) == 1 {
+		return json.Marshal(t[0])
+	}
+	return json.Marshal([]string(t))
+}
+
+func (t *Types) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err == nil {
+		*t = Types{name}
+		return nil
+	}
+	return json.Unmarshal(data, (*[]string)(t))
+}
+
+func (t Types) String() string {
+	return strings.Join(t, " or ")
+}
+
+func (t Types) has(typ string) bool {
+	for _, name := range t {
+		if name == typ {
+			return true
+		}
+	}
+	return false
+}
+
+// Property is a named entry of a schema's properties.
+type Property struct {
+	Name   string
+	Schema *Schema
+}
+
+// Properties keeps object properties in document order, so the exported
+// schema and the reported violations follow the order of the source file.
+type Properties []Property
+
+// Lookup returns the schema of the named property.
+func (p Properties) Lookup(name string) (*Schema, bool) {
+	for _, prop := range p {
+		if prop.Name == name {
+			return prop.Schema, true
+		}
+	}
+	return nil, false
+}
+
+func (p Properties) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, prop := range p {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		name, err := json.Marshal(prop.Name)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(prop.Schema)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+func (p *Properties) UnmarshalJSON(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if _, err := dec.Token(); err != nil { // opening brace
+		return err
+	}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		name, ok := tok.(string)
+		if !ok {
+			return fmt.Errorf("invalid property name %v", tok)
+		}
+		var s Schema
+		if err := dec.Decode(&s); err != nil {
+			return fmt.Errorf("property %q: %w", name, err)
+		}
+		*p = append(*p, Property{Name: name, Schema: &s})
+	}
+	return nil
+}
+
+// Metadata returns the metadata.yaml schema, with its required fields taken
+// from the repository configuration.
+func Metadata(cfg *config.Config) (*Schema, error) {
+	var s Schema
+	if err := json.Unmarshal(metadataSchema, &s); err != nil {
+		return nil, fmt.Errorf("embedded metadata schema: %w", err)
+	}
+	s.Required = append([]string(nil), cfg.Metadata.Required...)
+
+	if err := s.compile(); err != nil {
+		return nil, fmt.Errorf("embedded metadata schema: %w", err)
+	}
+	return &s, nil
+}
+
+func (s *Schema) compile() error {
+	if s.Pattern != "" {
+		re, err := regexp.Compile(s.Pattern)
+		if err != nil {
+			return err
+		}
+		s.pattern = re
+	}
+	for _, prop := range s.Properties {
+		if err := prop.Schema.compile(); err != nil {
+			return err
+		}
+	}
+	if s.Additional != nil {
+		if err := s.Additional.compile(); err != nil {
+			return err
+		}
+	}
+	if s.Items != nil {
+		return s.Items.compile()
+	}
+	return nil
+}
+
+// JSON returns the schema as indented JSON, ready to be saved for editors.
+func (s *Schema) JSON() ([]byte, error) {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// Violation is a place where a document does not match the schema.
+type Violation struct {
+	Path    string // e.g. topics[2]; empty for the document itself
+	Message string
+	Line    int
+	Column  int
+}
+
+func (v Violation) String() string {
+	path := v.Path
+	if path == "" {
+		path = "(root)"
+	}
+	return path + ": " + v.Message
+}
+
+// ValidateYAML parses a YAML document and validates it against the schema.
+// The error is only set when data is not valid YAML.
+func (s *Schema) ValidateYAML(data []byte) ([]Violation, *yaml.Node, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, nil, err
+	}
+
+	node := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Line: 1, Column: 1}
+	if doc.Kind == yaml.DocumentNode && len(doc.Content) > 0 {
+		node = doc.Content[0]
+	}
+
+	return s.Validate(node), node, nil
+}
+
+// Validate validates a YAML node against the schema.
+func (s *Schema) Validate(node *yaml.Node) []Violation {
+	var violations []Violation
+	s.validate(node, "", &violations)
+	return violations
+}
+
+func (s *Schema) validate(node *yaml.Node, path string, out *[]Violation) {
+	for node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+
+	report := func(format string, args ...any) {
+		*out = append(*out, Violation{
+			Path:    path,
+			Message: fmt.Sprintf(format, args...),
+			Line:    node.Line,
+			Column:  node.Column,
+		})
+	}
+
+	if len(s.Type) > 0 && !hasType(node, s.Type) {
+		if len(s.Type) == 1 && s.Type[0] == "string" && isNull(node) && s.MinLength != nil && *s.MinLength > 0 {
+			report("must be non-empty string")
+		} else {
+			report("must be %s, got %s", s.Type, typeOf(node))
+		}
+		return
+	}
+
+	switch node.Kind {
+	case yaml.MappingNode:
+		for _, name := range s.Required {
+			if mappingValue(node, name) == nil {
+				*out = append(*out, Violation{
+					Path:    joinKey(path, name),
+					Message: "is required",
+					Line:    node.Line,
+					Column:  node.Column,
+				})
+			}
+		}
+		if s.MinProps != nil && len(node.Content)/2 < *s.MinProps {
+			if *s.MinProps == 1 {
+				report("must not be empty")
+			} else {
+				report("must have at least %d properties", *s.MinProps)
+			}
+		}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i].Value, node.Content[i+1]
+			if prop, ok := s.Properties.Lookup(key); ok {
+				prop.validate(value, joinKey(path, key), out)
+			} else if s.Additional != nil {
+				s.Additional.validate(value, joinKey(path, key), out)
+			}
+		}
+
+	case yaml.SequenceNode:
+		if s.MinItems != nil && len(node.Content) < *s.MinItems {
+			if *s.MinItems == 1 {
+				report("must not be empty")
+			} else {
+				report("must have at least %d items", *s.MinItems)
+			}
+		}
+		if s.Items != nil {
+			for i, item := range node.Content {
+				s.Items.validate(item, path+"["+strconv.Itoa(i)+"]", out)
+			}
+		}
+
+	case yaml.ScalarNode:
+		if typeOf(node) != "string" {
+			return
+		}
+		if s.MinLength != nil && utf8.RuneCountInString(node.Value) < *s.MinLength {
+			if *s.MinLength == 1 {
+				report("must be non-empty string")
+			} else {
+				report("must be at least %d characters", *s.MinLength)
+			}
+			return
+		}
+		if s.pattern != nil && !s.pattern.MatchString(node.Value) {
+			report("must match pattern %s", s.Pattern)
+		}
+	}
+}
+
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+func joinKey(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func isNull(node *yaml.Node) bool {
+	return node.Kind == yaml.ScalarNode && node.Tag == "!!null"
+}
+
+func hasType(node *yaml.Node, types Types) bool {
+	typ := typeOf(node)
+	return types.has(typ) || (typ == "integer" && types.has("number"))
+}
+
+// typeOf returns the JSON Schema type name of a YAML node.
+func typeOf(node *yaml.Node) string {
+	switch node.Kind {
+	case yaml.MappingNode:
+		return "object"
+	case yaml.SequenceNode:
+		return "array"
+	}
+	switch node.Tag {
+	case "!!null":
+		return "null"
+	case "!!bool":
+		return "boolean"
+	case "!!int":
+		return "integer"
+	case "!!float":
+		return "number"
+	}
+	return "string"
+}
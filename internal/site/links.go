@@ -0,0 +1,55 @@
+package site
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var schemeRe = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9+.-]*:`)
+
+// assetExts are the extensions of files that are copied into the site when
+// a README links to them. Images are always copied.
+var assetExts = map[string]bool{
+	".png": true, ".jpg": true, ".jpeg": true, ".gif": true, ".svg": true, ".webp": true,
+	".pdf": true, ".mp4": true, ".webm": true,
+}
+
+// linker returns the link rewriter for the README of the talk at dir, shown
+// on a page whose relative link to the site root is root. Relative links
+// to talk directories and READMEs become links to the talk pages, images
+// and assets are copied into the site, and other files and directories
+// link to source_url when it is set.
+func (b *builder) linker(dir, root string) func(string, bool) string {
+	return func(dest string, image bool) string {
+		if dest == "" || strings.HasPrefix(dest, "#") || strings.HasPrefix(dest, "/") || schemeRe.MatchString(dest) {
+			return dest
+		}
+
+		target, fragment := dest, ""
+		if i := strings.IndexAny(dest, "?#"); i != -1 {
+			target, fragment = dest[:i], dest[i:]
+		}
+		p := path.Join(dir, target)
+		if p == ".." || strings.HasPrefix(p, "../") {
+			return dest
+		}
+
+		if page, ok := b.pages[p]; ok {
+			return root + page + fragment
+		}
+
+		info, err := os.Stat(filepath.Join(b.root, filepath.FromSlash(p)))
+		if err == nil && info.Mode().IsRegular() && (image || assetExts[strings.ToLower(path.Ext(p))]) {
+			b.assets[p] = true
+			return root + p + fragment
+		}
+
+		if err == nil && b.cfg.SourceURL != "" {
+			return strings.TrimSuffix(b.cfg.SourceURL, "/") + "/" + p + fragment
+		}
+		return dest
+	}
+}
@@ -0,0 +1,227 @@
+package site
+
+import (
+	"fmt"
+	"html/template"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/shankyjs/talks/internal/config"
+	"github.com/shankyjs/talks/internal/index"
+	"github.com/shankyjs/talks/internal/stats"
+	"github.com/shankyjs/talks/internal/talkrepo"
+)
+
+// Page is the data passed to the site templates. Paths are site paths
+// without language suffix and extension, e.g. "years/2025"; Href turns
+// them into links relative to the page.
+type Page struct {
+	Kind      string // "home", "year", "topic", "event" or "talk"
+	Lang      string
+	Path      string
+	Title     string
+	Languages []Alternate // this page in every language
+	Stats     index.Stats
+
+	Upcoming  []Talk        // home: upcoming talks
+	Years     []Group       // home: talks by year
+	Events    []Group       // home: talks by event
+	Topics    []index.Topic // home: the topic tree; topic pages: subtopics
+	Talks     []Talk        // year, topic and event pages: their talks
+	Talk      *Talk         // talk pages: the talk
+	Content   template.HTML // talk pages: the talk README
+	SourceURL string        // talk pages: the talk directory at source_url, if set
+
+	catalog *catalog
+	suffix  string
+}
+
+// Alternate is a page in one of the languages.
+type Alternate struct {
+	Code    string
+	Href    string
+	Current bool // the language of the page being rendered
+}
+
+// Talk is a talk as shown in the site.
+type Talk struct {
+	index.Talk
+	URL        string // site path of the talk page
+	TopicLinks []Link
+	EventLink  *Link // nil when the event has no page
+}
+
+// Link is a labelled site path.
+type Link struct {
+	Label string
+	Path  string
+}
+
+// Group is a year or event with its talks.
+type Group struct {
+	Kind  string // "year" or "event"
+	Name  string
+	Path  string
+	Talks []Talk
+}
+
+// TalkList is the data of the "talk-list" template.
+type TalkList struct {
+	Page  *Page
+	Talks []Talk
+}
+
+// TopicTree is the data of the "topic-tree" template.
+type TopicTree struct {
+	Page   *Page
+	Topics []index.Topic
+}
+
+// Href returns the link from p to the page at the site path in p's
+// language.
+func (p *Page) Href(sitePath string) string {
+	return p.root() + sitePath + p.suffix + ".html"
+}
+
+// Asset returns the link from p to a file at the site root.
+func (p *Page) Asset(name string) string {
+	return p.root() + name
+}
+
+// TopicHref returns the link from p to the page of a canonical topic.
+func (p *Page) TopicHref(key string) string {
+	return p.Href(p.catalog.topicPath(key))
+}
+
+// List returns the data for rendering talks with the "talk-list" template.
+func (p *Page) List(talks []Talk) TalkList {
+	return TalkList{p, talks}
+}
+
+// Tree returns the data for rendering topics with the "topic-tree"
+// template.
+func (p *Page) Tree(topics []index.Topic) TopicTree {
+	return TopicTree{p, topics}
+}
+
+// root returns the relative link from p to the site root.
+func (p *Page) root() string {
+	return strings.Repeat("../", strings.Count(p.Path, "/"))
+}
+
+// catalog is the talks of one language arranged into site pages.
+type catalog struct {
+	talks      []Talk
+	byPath     map[string]Talk
+	years      []Group
+	events     []Group
+	topics     []index.Topic     // every node of the topic tree
+	topicPaths map[string]string // site path of each canonical topic
+}
+
+// newCatalog arranges data, built from talks, into site pages.
+func newCatalog(talks []talkrepo.Talk, cfg *config.Config, data index.Data) (*catalog, error) {
+	c := &catalog{byPath: make(map[string]Talk), topicPaths: make(map[string]string)}
+
+	var walk func(topics []index.Topic)
+	walk = func(topics []index.Topic) {
+		for _, topic := range topics {
+			c.topics = append(c.topics, topic)
+			walk(topic.Children)
+		}
+	}
+	walk(data.Topics)
+
+	used := make(map[string]string)
+	for _, topic := range c.topics {
+		slug := slugify(topic.Key)
+		if other, ok := used[slug]; ok {
+			return nil, fmt.Errorf("topics %q and %q would both be published as topics/%s", other, topic.Key, slug)
+		}
+		used[slug] = topic.Key
+		c.topicPaths[topic.Key] = "topics/" + slug
+	}
+
+	eventPaths := make(map[string]string)
+	usedEvents := make(map[string]string)
+	for _, event := range data.Events {
+		slug := slugify(event.Name)
+		if other, ok := usedEvents[slug]; ok {
+			return nil, fmt.Errorf("events %q and %q would both be published as events/%s", other, event.Name, slug)
+		}
+		usedEvents[slug] = event.Name
+		eventPaths[event.Name] = "events/" + slug
+	}
+
+	// data.Talks is in the order of talks, so their topics line up
+	for i, t := range data.Talks {
+		talk := Talk{Talk: t, URL: t.Path + "/index"}
+		for j, key := range stats.Topics(talks[i], cfg) {
+			talk.TopicLinks = append(talk.TopicLinks, Link{t.Topics[j], c.topicPaths[key]})
+		}
+		if p, ok := eventPaths[t.Event]; ok {
+			talk.EventLink = &Link{t.Event, p}
+		}
+		c.talks = append(c.talks, talk)
+		c.byPath[t.Path] = talk
+	}
+
+	for _, year := range data.Years {
+		c.years = append(c.years, Group{"year", year.Year, "years/" + year.Year, c.list(year.Talks)})
+	}
+	for _, event := range data.Events {
+		var eventTalks []Talk
+		for _, t := range c.talks {
+			if t.Event == event.Name {
+				eventTalks = append(eventTalks, t)
+			}
+		}
+		c.events = append(c.events, Group{"event", event.Name, eventPaths[event.Name], eventTalks})
+	}
+	return c, nil
+}
+
+// list returns the site talks of index talks.
+func (c *catalog) list(talks []index.Talk) []Talk {
+	var list []Talk
+	for _, t := range talks {
+		list = append(list, c.byPath[t.Path])
+	}
+	return list
+}
+
+func (c *catalog) topicPath(key string) string {
+	return c.topicPaths[key]
+}
+
+// slugify turns a name into a file name: lowercase letters and digits, with
+// hyphens for everything else.
+func slugify(name string) string {
+	var b strings.Builder
+	hyphen := false
+	for _, r := range strings.ToLower(name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if hyphen && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			hyphen = false
+		} else {
+			hyphen = true
+		}
+	}
+	if b.Len() == 0 {
+		return "-"
+	}
+	return b.String()
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,327 @@
+// Package site builds the talks catalog as a self-contained static HTML
+// site: a home page, a page per year, topic and event, and a page per talk
+// rendering its README, in every configured language.
+//
+// The first configured language is the default: its pages are index.html,
+// years/2025.html, ... and every other language adds its code before the
+// extension (index.es.html). Talk pages sit in the talk directory
+// (2025/<talk>/index.html), so the images and files their READMEs link to
+// are copied to the same relative path.
+//
+// Pages are rendered from html/template files: the embedded defaults in
+// templates/, each of which the repository can override with a file of the
+// same name in the site subdirectory of its templates directory. The
+// templates receive a *Page and can call these functions:
+//
+//	msg KEY          the message KEY from the language's catalog
+//	join LIST SEP    strings.Join
+//	upper TEXT       strings.ToUpper
+package site
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/shankyjs/talks/internal/config"
+	"github.com/shankyjs/talks/internal/index"
+	"github.com/shankyjs/talks/internal/markdown"
+	"github.com/shankyjs/talks/internal/messages"
+	"github.com/shankyjs/talks/internal/talkrepo"
+)
+
+//go:embed templates/*
+var templatesFS embed.FS
+
+// Template and stylesheet names. Every page template defines "content",
+// which the layout template wraps.
+const (
+	LayoutTemplate = "layout.html.tmpl"
+	HomeTemplate   = "home.html.tmpl"
+	ListTemplate   = "list.html.tmpl"
+	TalkTemplate   = "talk.html.tmpl"
+	Stylesheet     = "style.css"
+)
+
+// TemplateDir is the subdirectory of the templates directory holding the
+// site template overrides.
+const TemplateDir = "site"
+
+// markerFile marks a directory written by Build, which later builds may
+// clear.
+const markerFile = ".talks-site"
+
+// Summary counts the files Build wrote.
+type Summary struct {
+	Pages  int
+	Assets int
+}
+
+type builder struct {
+	root   string
+	out    string
+	cfg    *config.Config
+	talks  []talkrepo.Talk
+	asOf   string
+	pages  map[string]string // site path of the talk page for each talk directory and README
+	assets map[string]bool   // files to copy, relative to the root
+	count  Summary
+}
+
+// Build writes the site for talks, sorted newest first, into out (relative
+// to root unless absolute). Talks dated before asOf (YYYY-MM-DD) count as
+// past talks. It clears out first, refusing to when out holds files it did
+// not write.
+func Build(root string, cfg *config.Config, talks []talkrepo.Talk, asOf, out string) (Summary, error) {
+	if !filepath.IsAbs(out) {
+		out = filepath.Join(root, out)
+	}
+	if err := prepare(root, out); err != nil {
+		return Summary{}, err
+	}
+
+	b := &builder{
+		root:   root,
+		out:    out,
+		cfg:    cfg,
+		talks:  talks,
+		asOf:   asOf,
+		assets: make(map[string]bool),
+	}
+
+	for i, lang := range cfg.Languages {
+		if err := b.language(lang, i); err != nil {
+			return Summary{}, err
+		}
+	}
+
+	for _, p := range sortedKeys(b.assets) {
+		if err := copyFile(filepath.Join(root, filepath.FromSlash(p)), filepath.Join(out, filepath.FromSlash(p))); err != nil {
+			return Summary{}, err
+		}
+		b.count.Assets++
+	}
+
+	style, _, err := readTemplate(root, cfg.Templates, Stylesheet)
+	if err != nil {
+		return Summary{}, err
+	}
+	if err := os.WriteFile(filepath.Join(out, Stylesheet), style, 0644); err != nil {
+		return Summary{}, err
+	}
+
+	return b.count, os.WriteFile(filepath.Join(out, markerFile), nil, 0644)
+}
+
+// prepare empties out, or creates it.
+func prepare(root, out string) error {
+	if rel, err := filepath.Rel(out, root); err == nil && !strings.HasPrefix(rel, "..") {
+		return fmt.Errorf("output directory %s contains the repository", out)
+	}
+
+	entries, err := os.ReadDir(out)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+	case err != nil:
+		return err
+	case len(entries) == 0:
+	default:
+		if _, err := os.Stat(filepath.Join(out, markerFile)); err != nil {
+			return fmt.Errorf("output directory %s is not empty and was not built by talks site", out)
+		}
+		if err := os.RemoveAll(out); err != nil {
+			return err
+		}
+	}
+	return os.MkdirAll(out, 0755)
+}
+
+// language writes every page in the i-th configured language.
+func (b *builder) language(lang config.Language, i int) error {
+	msgs, err := messages.Load(b.root, b.cfg.Messages, lang.Code)
+	if err != nil {
+		return err
+	}
+
+	tmpl, err := loadTemplates(b.root, b.cfg.Templates, msgs)
+	if err != nil {
+		return err
+	}
+
+	b.pages = make(map[string]string)
+	for _, t := range b.talks {
+		b.pages[t.Path] = t.Path + "/index" + b.cfg.Suffix(lang.Code) + ".html"
+		for _, l := range b.cfg.Languages {
+			b.pages[path.Join(t.Path, l.TalkReadme)] = t.Path + "/index" + b.cfg.Suffix(l.Code) + ".html"
+		}
+	}
+	for _, l := range b.cfg.Languages {
+		b.pages[l.Readme] = "index" + b.cfg.Suffix(l.Code) + ".html"
+	}
+
+	data := index.NewData(b.talks, b.cfg, lang.Code, b.asOf, nil)
+	c, err := newCatalog(b.talks, b.cfg, data)
+	if err != nil {
+		return err
+	}
+
+	base := Page{Lang: lang.Code, Stats: data.Stats, catalog: c, suffix: b.cfg.Suffix(lang.Code)}
+	render := func(name string, p Page) error {
+		return b.write(tmpl[name], p, i)
+	}
+
+	home := base
+	home.Kind, home.Path = "home", "index"
+	home.Years, home.Events, home.Topics = c.years, c.events, data.Topics
+	for _, t := range c.talks {
+		if t.Upcoming {
+			home.Upcoming = append(home.Upcoming, t)
+		}
+	}
+	if err := render(HomeTemplate, home); err != nil {
+		return err
+	}
+
+	for _, group := range append(c.years, c.events...) {
+		p := base
+		p.Kind, p.Path, p.Title, p.Talks = group.Kind, group.Path, group.Name, group.Talks
+		if err := render(ListTemplate, p); err != nil {
+			return err
+		}
+	}
+
+	for _, topic := range c.topics {
+		p := base
+		p.Kind, p.Path, p.Title = "topic", c.topicPath(topic.Key), topic.Name
+		p.Talks, p.Topics = c.list(topic.Talks), topic.Children
+		if err := render(ListTemplate, p); err != nil {
+			return err
+		}
+	}
+
+	for j := range c.talks {
+		t := &c.talks[j]
+		p := base
+		p.Kind, p.Path, p.Title, p.Talk = "talk", t.URL, t.Title, t
+		if b.cfg.SourceURL != "" {
+			p.SourceURL = strings.TrimSuffix(b.cfg.SourceURL, "/") + "/" + t.Path
+		}
+		p.Content, err = b.readme(t.Path, i, p.root())
+		if err != nil {
+			return err
+		}
+		if err := render(TalkTemplate, p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readme renders the README of the talk at dir in the i-th configured
+// language, or in the first other language that has one.
+func (b *builder) readme(dir string, i int, root string) (template.HTML, error) {
+	langs := append([]config.Language{b.cfg.Languages[i]}, b.cfg.Languages...)
+	for _, lang := range langs {
+		src, err := os.ReadFile(filepath.Join(b.root, filepath.FromSlash(dir), lang.TalkReadme))
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		html := markdown.ToHTML(src, markdown.Options{Link: b.linker(dir, root)})
+		return template.HTML(html), nil
+	}
+	return "", nil
+}
+
+// write renders page p with tmpl to its file in the i-th language.
+func (b *builder) write(tmpl *template.Template, p Page, i int) error {
+	for j, lang := range b.cfg.Languages {
+		p.Languages = append(p.Languages, Alternate{
+			Code:    lang.Code,
+			Href:    p.root() + p.Path + b.cfg.Suffix(lang.Code) + ".html",
+			Current: j == i,
+		})
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&buf, LayoutTemplate, &p); err != nil {
+		return err
+	}
+
+	file := filepath.Join(b.out, filepath.FromSlash(p.Path+p.suffix+".html"))
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return err
+	}
+	b.count.Pages++
+	return os.WriteFile(file, buf.Bytes(), 0644)
+}
+
+// loadTemplates parses the page templates, preferring the files in the site
+// subdirectory of dir under root over the embedded defaults. It returns
+// each page template together with the layout.
+func loadTemplates(root, dir string, msgs messages.Catalog) (map[string]*template.Template, error) {
+	layout := template.New("").Funcs(template.FuncMap{
+		"msg":   msgs.Get,
+		"join":  strings.Join,
+		"upper": strings.ToUpper,
+	})
+	if err := parseTemplate(layout, root, dir, LayoutTemplate); err != nil {
+		return nil, err
+	}
+
+	pages := make(map[string]*template.Template)
+	for _, name := range []string{HomeTemplate, ListTemplate, TalkTemplate} {
+		tmpl, err := layout.Clone()
+		if err != nil {
+			return nil, err
+		}
+		if err := parseTemplate(tmpl, root, dir, name); err != nil {
+			return nil, err
+		}
+		pages[name] = tmpl
+	}
+	return pages, nil
+}
+
+func parseTemplate(tmpl *template.Template, root, dir, name string) error {
+	content, file, err := readTemplate(root, dir, name)
+	if err != nil {
+		return err
+	}
+	if _, err := tmpl.New(name).Parse(string(content)); err != nil {
+		return fmt.Errorf("parsing %s: %w", file, err)
+	}
+	return nil
+}
+
+// readTemplate returns the site file name from the site subdirectory of dir
+// under root, or the embedded default, with the path it was read from.
+func readTemplate(root, dir, name string) ([]byte, string, error) {
+	file := filepath.ToSlash(filepath.Join(dir, TemplateDir, name))
+	content, err := os.ReadFile(filepath.Join(root, dir, TemplateDir, name))
+	if errors.Is(err, fs.ErrNotExist) {
+		file = "templates/" + name
+		content, err = templatesFS.ReadFile(file)
+	}
+	return content, file, err
+}
+
+func copyFile(src, dst string) error {
+	content, err := os.ReadFile(src)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(dst, content, 0644)
+}
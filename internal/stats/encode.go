@@ -0,0 +1,86 @@
+package stats
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"strconv"
+)
+
+// Formats are the formats the report can be rendered in.
+var Formats = []string{"markdown", "json", "csv", "svg"}
+
+// Render renders r in format, one of Formats.
+func Render(r Report, format string) ([]byte, error) {
+	switch format {
+	case "json":
+		return JSON(r)
+	case "csv":
+		return CSV(r)
+	case "svg":
+		return SVG(r), nil
+	default:
+		return []byte(Markdown(r)), nil
+	}
+}
+
+// JSON renders r as indented JSON.
+func JSON(r Report) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(r); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// CSV renders the counts of r as CSV rows of section, name, period and
+// count: the totals ("total", "past", "upcoming"), every year, quarter and
+// month (as periods), every topic and event (as names) and their count in
+// each year (as name and period), then the longest gap and the average lead
+// time in days, with the dates they span as period, and the talks of every
+// contributor.
+func CSV(r Report) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	rows := [][]string{
+		{"section", "name", "period", "count"},
+		{"total", "", "", strconv.Itoa(r.Total)},
+		{"past", "", "", strconv.Itoa(r.Past)},
+		{"upcoming", "", "", strconv.Itoa(r.Upcoming)},
+	}
+	for _, section := range []struct {
+		name   string
+		counts []Count
+	}{{"year", r.Years}, {"quarter", r.Quarters}, {"month", r.Months}} {
+		for _, c := range section.counts {
+			rows = append(rows, []string{section.name, "", c.Name, strconv.Itoa(c.Count)})
+		}
+	}
+	for _, section := range []struct {
+		name   string
+		trends []Trend
+	}{{"topic", r.TopicTrends}, {"event", r.EventTrends}} {
+		for _, t := range section.trends {
+			rows = append(rows, []string{section.name, t.Name, "", strconv.Itoa(t.Total)})
+			for _, year := range t.Years {
+				rows = append(rows, []string{section.name, t.Name, year.Name, strconv.Itoa(year.Count)})
+			}
+		}
+	}
+	if r.LongestGap != nil {
+		rows = append(rows, []string{"longest_gap_days", "", r.LongestGap.From + "/" + r.LongestGap.To, strconv.Itoa(r.LongestGap.Days)})
+	}
+	if r.LeadTime != nil {
+		rows = append(rows, []string{"lead_time_days", "", "", strconv.FormatFloat(r.LeadTime.AverageDays, 'f', 1, 64)})
+	}
+	for _, c := range r.Contributors {
+		rows = append(rows, []string{"contributor", c.Name, "", strconv.Itoa(c.Count)})
+	}
+	if err := w.WriteAll(rows); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
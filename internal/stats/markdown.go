@@ -0,0 +1,148 @@
+package stats
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Markdown renders r as the markdown report, with ASCII bars.
+func Markdown(r Report) string {
+	var sb strings.Builder
+
+	if r.Total == 0 {
+		sb.WriteString("## 📊 Talk Statistics\n\n")
+		sb.WriteString("No talks found yet. Create your first talk with:\n")
+		sb.WriteString("```bash\n")
+		sb.WriteString("make create-talk DATE=2025-12-01 SLUG=my-first-talk\n")
+		sb.WriteString("```\n")
+		return sb.String()
+	}
+
+	sb.WriteString("## 📊 Talk Statistics\n\n")
+	sb.WriteString(fmt.Sprintf("### 🎤 Total Talks: %d\n\n", r.Total))
+	sb.WriteString(fmt.Sprintf("- **Past Talks**: %d\n", r.Past))
+	sb.WriteString(fmt.Sprintf("- **Upcoming Talks**: %d\n\n", r.Upcoming))
+
+	// Talks by year
+	sb.WriteString("### 📅 Talks by Year\n\n")
+	for _, year := range r.Years {
+		bar := strings.Repeat("█", year.Count)
+		sb.WriteString(fmt.Sprintf("- **%s**: %d %s\n", year.Name, year.Count, bar))
+	}
+	sb.WriteString("\n")
+
+	// Top topics
+	sb.WriteString("### 🏷️ Most Popular Topics\n\n")
+	for _, t := range top(r.Topics, TopTopics) {
+		bar := strings.Repeat("█", t.Count)
+		sb.WriteString(fmt.Sprintf("- **%s**: %d %s\n", t.Name, t.Count, bar))
+	}
+	sb.WriteString("\n")
+
+	// Events
+	sb.WriteString("### 🎪 Events\n\n")
+	if len(r.Events) > 0 {
+		for _, e := range top(r.Events, TopEvents) {
+			sb.WriteString(fmt.Sprintf("- **%s**: %d talks\n", e.Name, e.Count))
+		}
+	} else {
+		sb.WriteString("No events with talks yet.\n")
+	}
+	sb.WriteString("\n")
+
+	// Time series
+	sb.WriteString("### 🗓️ Talks by Quarter\n\n")
+	writeBars(&sb, r.Quarters)
+	sb.WriteString("### 📆 Talks by Month\n\n")
+	writeBars(&sb, r.Months)
+
+	if len(r.TopicTrends) > 0 {
+		sb.WriteString("### 📈 Topic Trends\n\n")
+		writeTrends(&sb, "Topic", top(r.TopicTrends, TopTopics))
+	}
+	if len(r.EventTrends) > 0 {
+		sb.WriteString("### 🎪 Events by Year\n\n")
+		writeTrends(&sb, "Event", top(r.EventTrends, TopEvents))
+	}
+
+	sb.WriteString("### ⏱️ Cadence\n\n")
+	if r.LongestGap != nil {
+		sb.WriteString(fmt.Sprintf("- **Longest Gap**: %d days (%s → %s)\n", r.LongestGap.Days, r.LongestGap.From, r.LongestGap.To))
+	} else {
+		sb.WriteString("- **Longest Gap**: not enough talks yet\n")
+	}
+	if r.LeadTime != nil {
+		sb.WriteString(fmt.Sprintf("- **Average Lead Time**: %.0f days between adding a talk and giving it (%d talks)\n", r.LeadTime.AverageDays, r.LeadTime.Talks))
+	}
+	sb.WriteString("\n")
+
+	// Upcoming talks
+	if len(r.Next) > 0 {
+		sb.WriteString("### 🔜 Upcoming Talks\n\n")
+		for i, talk := range r.Next {
+			if i >= NextTalks {
+				break
+			}
+			if talk.Event != "" {
+				sb.WriteString(fmt.Sprintf("- **%s**: %s @ %s\n", talk.Date, talk.Title, talk.Event))
+			} else {
+				sb.WriteString(fmt.Sprintf("- **%s**: %s\n", talk.Date, talk.Title))
+			}
+		}
+		sb.WriteString("\n")
+	}
+
+	// Git history
+	if len(r.Contributors) > 0 {
+		sb.WriteString("### 👥 Contributors\n\n")
+		for _, c := range top(r.Contributors, TopAuthors) {
+			sb.WriteString(fmt.Sprintf("- **%s**: %d talks\n", c.Name, c.Count))
+		}
+		sb.WriteString("\n")
+	}
+	if len(r.Updated) > 0 {
+		sb.WriteString("### 🛠️ Recently Updated\n\n")
+		for _, talk := range top(r.Updated, UpdatedTalks) {
+			sb.WriteString(fmt.Sprintf("- **%s**: %s (%s)\n", talk.Updated, talk.Title, talk.Path))
+		}
+		sb.WriteString("\n")
+	}
+
+	return sb.String()
+}
+
+// writeBars writes counts as a list with ASCII bars.
+func writeBars(sb *strings.Builder, counts []Count) {
+	for _, c := range counts {
+		sb.WriteString(strings.TrimRight(fmt.Sprintf("- **%s**: %d %s", c.Name, c.Count, strings.Repeat("█", c.Count)), " ") + "\n")
+	}
+	sb.WriteString("\n")
+}
+
+// writeTrends writes trends as a table with a column per year and the
+// change over the last year.
+func writeTrends(sb *strings.Builder, name string, trends []Trend) {
+	header, rule := "| "+name+" |", "|---|"
+	for _, year := range trends[0].Years {
+		header += " " + year.Name + " |"
+		rule += "---:|"
+	}
+	sb.WriteString(header + " Trend |\n" + rule + ":---:|\n")
+
+	for _, t := range trends {
+		row := "| " + t.Name + " |"
+		for _, year := range t.Years {
+			row += fmt.Sprintf(" %d |", year.Count)
+		}
+		switch {
+		case t.Change > 0:
+			row += fmt.Sprintf(" ⬆️ +%d |", t.Change)
+		case t.Change < 0:
+			row += fmt.Sprintf(" ⬇️ %d |", t.Change)
+		default:
+			row += " ➡️ |"
+		}
+		sb.WriteString(row + "\n")
+	}
+	sb.WriteString("\n")
+}
@@ -0,0 +1,210 @@
+// Package stats computes the talk statistics and renders them as the
+// detailed markdown report (stats.txt), JSON, CSV or SVG bar charts.
+//
+// Compute is the only place aggregates are computed: the README summary of
+// the index package is built from the same Report, so both always agree on
+// which talks are past or upcoming and on which scaffold placeholders
+// (events and topics) are left out of the counts. The export, feed and
+// calendar use IsUpcoming, Event and Topics for the same decisions.
+package stats
+
+import (
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/shankyjs/talks/internal/config"
+	"github.com/shankyjs/talks/internal/history"
+	"github.com/shankyjs/talks/internal/talkrepo"
+)
+
+// Report holds the raw aggregates every format is rendered from.
+type Report struct {
+	AsOf     string  `json:"as_of"`
+	Total    int     `json:"total"`
+	Past     int     `json:"past"`
+	Upcoming int     `json:"upcoming"`
+	Years    []Count `json:"years"`  // newest first
+	Topics   []Count `json:"topics"` // canonical names, most talks first
+	Events   []Count `json:"events"` // most talks first
+	Next     []Talk  `json:"next"`   // upcoming talks, soonest first
+
+	Months      []Count   `json:"months"`              // YYYY-MM, newest first, including months without talks
+	Quarters    []Count   `json:"quarters"`            // YYYY-Qn, newest first, including quarters without talks
+	TopicTrends []Trend   `json:"topic_trends"`        // in the order of Topics
+	EventTrends []Trend   `json:"event_trends"`        // in the order of Events
+	LongestGap  *Gap      `json:"longest_gap"`         // nil with fewer than two talks
+	LeadTime    *LeadTime `json:"lead_time,omitempty"` // nil without git history
+
+	Contributors []Count `json:"contributors,omitempty"` // talks each commit author worked on, most talks first; empty without git history or stats.history
+	Updated      []Talk  `json:"updated,omitempty"`      // talks by their last commit, most recent first; empty without git history or stats.history
+}
+
+// Count is the number of talks of a year, topic or event.
+type Count struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// Talk is an upcoming or recently updated talk.
+type Talk struct {
+	Date    string `json:"date"`
+	Title   string `json:"title"`
+	Event   string `json:"event,omitempty"` // empty for placeholder events
+	Path    string `json:"path"`
+	Updated string `json:"updated,omitempty"` // date of the last commit, in Report.Updated
+}
+
+// Report sections are cut to these lengths by the markdown and SVG
+// renderers; JSON and CSV carry every entry. The README summary shows the
+// SummaryTopics most used topics.
+const (
+	TopTopics     = 10
+	TopEvents     = 5
+	NextTalks     = 5
+	TopAuthors    = 10
+	UpdatedTalks  = 5
+	SummaryTopics = 3
+)
+
+// IsUpcoming reports whether talk has not happened as of asOf
+// (YYYY-MM-DD): talks on the as-of date itself are upcoming.
+func IsUpcoming(talk talkrepo.Talk, asOf string) bool {
+	return talk.Date >= asOf
+}
+
+// year returns the year of the date of talk, so every section counts a talk
+// in the same year even when it is filed under another year directory. A
+// talk without a valid date falls back to its directory.
+func year(talk talkrepo.Talk) string {
+	if date, err := time.Parse("2006-01-02", talk.Date); err == nil {
+		return strconv.Itoa(date.Year())
+	}
+	return talk.Year
+}
+
+// Event returns the event of talk, or "" when it is a scaffold
+// placeholder.
+func Event(talk talkrepo.Talk, cfg *config.Config) string {
+	if cfg.IsPlaceholderEvent(talk.Event) {
+		return ""
+	}
+	return talk.Event
+}
+
+// Topics returns the canonical topics of talk, without scaffold
+// placeholders.
+func Topics(talk talkrepo.Talk, cfg *config.Config) []string {
+	var topics []string
+	for _, topic := range cfg.Topics.Normalize(talk.Topics) {
+		if topic != "" && !cfg.IsPlaceholderTopic(topic) {
+			topics = append(topics, topic)
+		}
+	}
+	return topics
+}
+
+// Compute aggregates talks. Talks dated before asOf (YYYY-MM-DD) count as
+// past talks. hist is the git history of each talk path (see history.Load),
+// for the lead time and, with stats.history in talks.yaml, the contributors
+// and the recently updated talks; it may be nil.
+func Compute(talks []talkrepo.Talk, cfg *config.Config, asOf string, hist map[string]history.Talk) Report {
+	r := Report{
+		AsOf:        asOf,
+		Total:       len(talks),
+		Years:       []Count{},
+		Topics:      []Count{},
+		Events:      []Count{},
+		Next:        []Talk{},
+		Months:      []Count{},
+		Quarters:    []Count{},
+		TopicTrends: []Trend{},
+		EventTrends: []Trend{},
+	}
+
+	talksByYear := make(map[string]int)
+	topicCount := make(map[string]int)
+	eventCount := make(map[string]int)
+	talkTopics := make(map[string][]string)
+	authorCount := make(map[string]int)
+
+	for _, talk := range talks {
+		event := Event(talk, cfg)
+		if IsUpcoming(talk, asOf) {
+			r.Upcoming++
+			r.Next = append(r.Next, Talk{talk.Date, talk.Title.String(), event, talk.Path, ""})
+		} else {
+			r.Past++
+		}
+
+		talksByYear[year(talk)]++
+
+		talkTopics[talk.Path] = Topics(talk, cfg)
+		for _, topic := range talkTopics[talk.Path] {
+			topicCount[topic]++
+		}
+
+		if event != "" {
+			eventCount[event]++
+		}
+
+		if h, ok := hist[talk.Path]; ok && cfg.Stats.History {
+			r.Updated = append(r.Updated, Talk{talk.Date, talk.Title.String(), event, talk.Path, h.Updated})
+			for _, author := range h.Contributors {
+				authorCount[author]++
+			}
+		}
+	}
+
+	// Sort upcoming talks by date, then by directory
+	sort.Slice(r.Next, func(i, j int) bool {
+		if r.Next[i].Date != r.Next[j].Date {
+			return r.Next[i].Date < r.Next[j].Date
+		}
+		return r.Next[i].Path < r.Next[j].Path
+	})
+
+	// Sort updated talks by their last commit, then by directory
+	sort.Slice(r.Updated, func(i, j int) bool {
+		if r.Updated[i].Updated != r.Updated[j].Updated {
+			return r.Updated[i].Updated > r.Updated[j].Updated
+		}
+		return r.Updated[i].Path < r.Updated[j].Path
+	})
+
+	for year, count := range talksByYear {
+		r.Years = append(r.Years, Count{year, count})
+	}
+	sort.Slice(r.Years, func(i, j int) bool { return r.Years[i].Name > r.Years[j].Name })
+
+	r.Topics = byCount(topicCount)
+	r.Events = byCount(eventCount)
+	r.Contributors = byCount(authorCount)
+	r.timeSeries(talks, talkTopics, hist)
+	return r
+}
+
+// byCount returns the counts of m, highest first.
+func byCount(m map[string]int) []Count {
+	sorted := []Count{}
+	for k, v := range m {
+		sorted = append(sorted, Count{k, v})
+	}
+
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Count != sorted[j].Count {
+			return sorted[i].Count > sorted[j].Count
+		}
+		// Ties are sorted by name, so the report does not depend on map order
+		return sorted[i].Name < sorted[j].Name
+	})
+	return sorted
+}
+
+// top returns the first n entries of list.
+func top[T any](list []T, n int) []T {
+	if len(list) > n {
+		return list[:n]
+	}
+	return list
+}
@@ -0,0 +1,151 @@
+package stats_test
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/shankyjs/talks/internal/config"
+	"github.com/shankyjs/talks/internal/history"
+	"github.com/shankyjs/talks/internal/index"
+	"github.com/shankyjs/talks/internal/stats"
+	"github.com/shankyjs/talks/internal/talkrepo"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata/golden")
+
+// The fixture repository in testdata/repo has past and upcoming talks,
+// topic aliases, a localized title, and scaffold placeholders for the
+// event ("Conference/Meetup Name" and "Unknown") and the topics, which both
+// outputs must leave out of the counts alike. One talk has a Go app, a Helm
+// chart with a subchart and a Makefile for the materials column.
+const (
+	fixture = "testdata/repo"
+	asOf    = "2025-11-01"
+)
+
+// hist stands in for the git history of the fixture, which has none.
+var hist = map[string]history.Talk{
+	"2025/dec-1st-new-talk":                {Created: "2025-10-02", Updated: "2025-10-20", Contributors: []string{"Ana"}},
+	"2026/feb-10th-ebpf-for-go-developers": {Created: "2025-12-10", Updated: "2025-12-10", Contributors: []string{"Ana", "Luis"}},
+	"2024/mar-5th-intro-to-kubernetes":     {Created: "2024-04-01", Updated: "2025-01-15", Contributors: []string{"Luis"}}, // added after the talk
+}
+
+func load(t *testing.T) (*config.Config, []talkrepo.Talk) {
+	t.Helper()
+	cfg, err := config.Load(fixture)
+	if err != nil {
+		t.Fatal(err)
+	}
+	talks, _, err := talkrepo.Load(fixture)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cfg, talks
+}
+
+// golden compares got with the golden file name, or rewrites the file
+// with -update.
+func golden(t *testing.T, name string, got []byte) {
+	t.Helper()
+	path := filepath.Join("testdata", "golden", name)
+	if *update {
+		if err := os.WriteFile(path, got, 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%v (run go test ./internal/stats -update to create it)", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("output differs from %s (run go test ./internal/stats -update to accept it)\n--- got:\n%s\n--- want:\n%s", path, got, want)
+	}
+}
+
+func TestReportFormats(t *testing.T) {
+	cfg, talks := load(t)
+	cfg.Stats.History = true
+	report := stats.Compute(talks, cfg, asOf, hist)
+
+	for format, name := range map[string]string{
+		"markdown": "stats.md",
+		"json":     "stats.json",
+		"csv":      "stats.csv",
+		"svg":      "stats.svg",
+	} {
+		t.Run(format, func(t *testing.T) {
+			got, err := stats.Render(report, format)
+			if err != nil {
+				t.Fatal(err)
+			}
+			golden(t, name, got)
+		})
+	}
+}
+
+// TestSummaryMatchesReport checks that the README summary and the detailed
+// report count the same talks, topics and events.
+func TestSummaryMatchesReport(t *testing.T) {
+	cfg, talks := load(t)
+	report := stats.Compute(talks, cfg, asOf, nil)
+	summary := index.NewData(talks, cfg, "en", asOf, nil)
+
+	if got, want := summary.Stats.Total, report.Total; got != want {
+		t.Errorf("total: summary %d, report %d", got, want)
+	}
+	if got, want := summary.Stats.Past, report.Past; got != want {
+		t.Errorf("past: summary %d, report %d", got, want)
+	}
+	if got, want := summary.Stats.Upcoming, report.Upcoming; got != want {
+		t.Errorf("upcoming: summary %d, report %d", got, want)
+	}
+	if got, want := summary.Stats.ActiveYears, len(report.Years); got != want {
+		t.Errorf("active years: summary %d, report %d", got, want)
+	}
+
+	if len(summary.Stats.TopicCounts) != len(report.Topics) {
+		t.Fatalf("topics: summary %v, report %v", summary.Stats.TopicCounts, report.Topics)
+	}
+	for i, c := range report.Topics {
+		if got := summary.Stats.TopicCounts[i]; got.Name != c.Name || got.Count != c.Count {
+			t.Errorf("topic %d: summary %v, report %v", i, got, c)
+		}
+	}
+
+	if len(summary.Events) != len(report.Events) {
+		t.Fatalf("events: summary %v, report %v", summary.Events, report.Events)
+	}
+	for i, c := range report.Events {
+		if got := summary.Events[i]; got.Name != c.Name || got.Count != c.Count {
+			t.Errorf("event %d: summary %v, report %v", i, got, c)
+		}
+	}
+}
+
+// TestYearFromDate checks that a talk filed under the wrong year directory
+// is counted in the year of its date by every section.
+func TestYearFromDate(t *testing.T) {
+	talks := []talkrepo.Talk{{
+		Metadata: talkrepo.Metadata{Date: "2025-01-10", Topics: []string{"Go"}, Event: "GopherCon"},
+		Path:     "2024/jan-10th-misfiled",
+		Year:     "2024",
+	}}
+	report := stats.Compute(talks, config.Default(), asOf, nil)
+
+	if len(report.Years) != 1 || report.Years[0].Name != "2025" {
+		t.Errorf("years: %v, want 2025", report.Years)
+	}
+	for _, trends := range [][]stats.Trend{report.TopicTrends, report.EventTrends} {
+		if len(trends) != 1 || len(trends[0].Years) != 1 || trends[0].Years[0].Name != "2025" {
+			t.Errorf("trends: %v, want one in 2025", trends)
+		}
+	}
+	if len(report.Quarters) != 1 || report.Quarters[0].Name != "2025-Q1" {
+		t.Errorf("quarters: %v, want 2025-Q1", report.Quarters)
+	}
+}
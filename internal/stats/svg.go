@@ -0,0 +1,96 @@
+package stats
+
+import (
+	"fmt"
+	"html"
+	"strings"
+	"unicode/utf8"
+)
+
+// Layout of the SVG charts, in pixels.
+const (
+	svgWidth    = 640
+	svgPadding  = 16
+	svgLabel    = 200 // width of the name column
+	svgCount    = 40  // width of the count column
+	svgRow      = 24
+	svgBar      = 16
+	svgHeading  = 32
+	svgGap      = 16
+	svgMaxLabel = 28 // longer names are shortened, with the full name as tooltip
+)
+
+// svgStyle follows the light or dark theme of the page the image is
+// embedded in, as GitHub READMEs do.
+const svgStyle = `text{font:13px system-ui,-apple-system,"Segoe UI",Helvetica,Arial,sans-serif;fill:#24292f}` +
+	`.heading{font-size:15px;font-weight:600}.muted{fill:#57606a}` +
+	`@media (prefers-color-scheme:dark){text{fill:#e6edf3}.muted{fill:#8d96a0}}`
+
+// SVG renders r as horizontal bar charts of the talks per year and per
+// quarter, the most popular topics and the events, ready to embed in a
+// README.
+func SVG(r Report) []byte {
+	charts := []struct {
+		title string
+		color string
+		empty string
+		bars  []Count
+	}{
+		{"Talks by Year", "#4c8bf5", "No talks yet", r.Years},
+		{"Talks by Quarter", "#8e6bd8", "No talks yet", r.Quarters},
+		{"Most Popular Topics", "#34a853", "No topics yet", top(r.Topics, TopTopics)},
+		{"Events", "#f9ab00", "No events with talks yet", top(r.Events, TopEvents)},
+	}
+
+	var body strings.Builder
+	y := svgPadding
+	fmt.Fprintf(&body, `<text class="muted" x="%d" y="%d">Total talks: %d · Past: %d · Upcoming: %d</text>`+"\n",
+		svgPadding, y+13, r.Total, r.Past, r.Upcoming)
+	y += svgRow + svgGap
+
+	for _, chart := range charts {
+		fmt.Fprintf(&body, `<text class="heading" x="%d" y="%d">%s</text>`+"\n", svgPadding, y+15, html.EscapeString(chart.title))
+		y += svgHeading
+
+		if len(chart.bars) == 0 {
+			fmt.Fprintf(&body, `<text class="muted" x="%d" y="%d">%s</text>`+"\n", svgPadding, y+13, html.EscapeString(chart.empty))
+			y += svgRow + svgGap
+			continue
+		}
+
+		most := 0
+		for _, bar := range chart.bars {
+			most = max(most, bar.Count)
+		}
+		area := svgWidth - 2*svgPadding - svgLabel - svgCount
+		for _, bar := range chart.bars {
+			width := max(area*bar.Count/most, 2)
+			name := html.EscapeString(bar.Name)
+			fmt.Fprintf(&body, `<g><title>%s: %d</title>`, name, bar.Count)
+			fmt.Fprintf(&body, `<text x="%d" y="%d">%s</text>`, svgPadding, y+13, html.EscapeString(shorten(bar.Name)))
+			fmt.Fprintf(&body, `<rect x="%d" y="%d" width="%d" height="%d" rx="3" fill="%s"/>`,
+				svgPadding+svgLabel, y+(svgRow-svgBar)/2-2, width, svgBar, chart.color)
+			fmt.Fprintf(&body, `<text x="%d" y="%d">%d</text></g>`+"\n", svgPadding+svgLabel+width+6, y+13, bar.Count)
+			y += svgRow
+		}
+		y += svgGap
+	}
+	height := y - svgGap + svgPadding
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" role="img" aria-label="Talk statistics">`+"\n",
+		svgWidth, height, svgWidth, height)
+	sb.WriteString("<title>Talk statistics</title>\n")
+	sb.WriteString("<style>" + svgStyle + "</style>\n")
+	sb.WriteString(body.String())
+	sb.WriteString("</svg>\n")
+	return []byte(sb.String())
+}
+
+// shorten cuts name to svgMaxLabel runes.
+func shorten(name string) string {
+	if utf8.RuneCountInString(name) <= svgMaxLabel {
+		return name
+	}
+	return string([]rune(name)[:svgMaxLabel-1]) + "…"
+}
@@ -0,0 +1,131 @@
+package stats
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/shankyjs/talks/internal/history"
+	"github.com/shankyjs/talks/internal/talkrepo"
+)
+
+// Trend is the number of talks of a topic or event in each year.
+type Trend struct {
+	Name   string  `json:"name"`
+	Total  int     `json:"total"`
+	Years  []Count `json:"years"`  // every year of the report, oldest first
+	Change int     `json:"change"` // talks in the latest year minus the year before
+}
+
+// Gap is the time between two consecutive talks.
+type Gap struct {
+	Days int    `json:"days"`
+	From string `json:"from"` // date of the talk before the gap
+	To   string `json:"to"`   // date of the talk after it
+}
+
+// LeadTime is how long before their date talks were added to the
+// repository.
+type LeadTime struct {
+	AverageDays float64 `json:"average_days"`
+	Talks       int     `json:"talks"` // talks added on or before their date, which the average is over
+}
+
+// timeSeries fills in the monthly and quarterly counts, the topic and event
+// trends, the longest gap and, with the git history of the talks, the lead
+// time.
+func (r *Report) timeSeries(talks []talkrepo.Talk, topics map[string][]string, hist map[string]history.Talk) {
+	var dates []time.Time
+	months := make(map[string]int)
+	quarters := make(map[string]int)
+	for _, talk := range talks {
+		date, err := time.Parse("2006-01-02", talk.Date)
+		if err != nil {
+			continue
+		}
+		dates = append(dates, date)
+		months[date.Format("2006-01")]++
+		quarters[quarter(date)]++
+	}
+	sort.Slice(dates, func(i, j int) bool { return dates[i].Before(dates[j]) })
+
+	// Every month and quarter from the first talk to the last, newest first
+	if len(dates) > 0 {
+		first, last := dates[0], dates[len(dates)-1]
+		for m := time.Date(last.Year(), last.Month(), 1, 0, 0, 0, 0, time.UTC); !m.Before(time.Date(first.Year(), first.Month(), 1, 0, 0, 0, 0, time.UTC)); m = m.AddDate(0, -1, 0) {
+			month := m.Format("2006-01")
+			r.Months = append(r.Months, Count{month, months[month]})
+			if q := quarter(m); len(r.Quarters) == 0 || r.Quarters[len(r.Quarters)-1].Name != q {
+				r.Quarters = append(r.Quarters, Count{q, quarters[q]})
+			}
+		}
+	}
+
+	for i := 1; i < len(dates); i++ {
+		days := int(dates[i].Sub(dates[i-1]).Hours() / 24)
+		if r.LongestGap == nil || days > r.LongestGap.Days {
+			r.LongestGap = &Gap{days, dates[i-1].Format("2006-01-02"), dates[i].Format("2006-01-02")}
+		}
+	}
+
+	// Trends are over the years with talks, oldest first
+	years := make([]string, len(r.Years))
+	for i, year := range r.Years {
+		years[len(years)-1-i] = year.Name
+	}
+	topicYears := make(map[string]map[string]int)
+	eventYears := make(map[string]map[string]int)
+	for _, talk := range talks {
+		for _, topic := range topics[talk.Path] {
+			add(topicYears, topic, year(talk))
+		}
+		add(eventYears, talk.Event, year(talk))
+	}
+	for _, topic := range r.Topics {
+		r.TopicTrends = append(r.TopicTrends, trend(topic, years, topicYears[topic.Name]))
+	}
+	for _, event := range r.Events {
+		r.EventTrends = append(r.EventTrends, trend(event, years, eventYears[event.Name]))
+	}
+
+	var total, count int
+	for _, talk := range talks {
+		added, err := time.Parse("2006-01-02", hist[talk.Path].Created)
+		if err != nil {
+			continue
+		}
+		date, err := time.Parse("2006-01-02", talk.Date)
+		if err != nil || date.Before(added) {
+			continue
+		}
+		total += int(date.Sub(added).Hours() / 24)
+		count++
+	}
+	if count > 0 {
+		r.LeadTime = &LeadTime{float64(total) / float64(count), count}
+	}
+}
+
+// quarter returns the quarter of date, e.g. "2025-Q4".
+func quarter(date time.Time) string {
+	return fmt.Sprintf("%d-Q%d", date.Year(), (int(date.Month())+2)/3)
+}
+
+func add(m map[string]map[string]int, name, year string) {
+	if m[name] == nil {
+		m[name] = make(map[string]int)
+	}
+	m[name][year]++
+}
+
+// trend returns the trend of c over years, given its count in each year.
+func trend(c Count, years []string, byYear map[string]int) Trend {
+	t := Trend{Name: c.Name, Total: c.Count, Years: []Count{}}
+	for _, year := range years {
+		t.Years = append(t.Years, Count{year, byYear[year]})
+	}
+	if n := len(t.Years); n >= 2 {
+		t.Change = t.Years[n-1].Count - t.Years[n-2].Count
+	}
+	return t
+}
@@ -0,0 +1,97 @@
+package talkrepo
+
+import (
+	"encoding/json"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Localized is a metadata value that is either a plain string, used for
+// every language, or a mapping from language code to the value in that
+// language:
+//
+//	title: "Intro to Flux"
+//	title:
+//	  en: "GitOps in 30 minutes"
+//	  es: "GitOps en 30 minutos"
+type Localized struct {
+	Value  string            // the plain string form
+	ByLang map[string]string // the mapping form
+}
+
+// Text returns the value for the language lang. When there is no value for
+// lang it falls back to the plain string, and then to the first language in
+// alphabetical order.
+func (l Localized) Text(lang string) string {
+	if text := l.ByLang[lang]; text != "" {
+		return text
+	}
+	if l.Value != "" {
+		return l.Value
+	}
+	for _, code := range l.Langs() {
+		if text := l.ByLang[code]; text != "" {
+			return text
+		}
+	}
+	return ""
+}
+
+// String returns the value without a language preference.
+func (l Localized) String() string {
+	return l.Text("")
+}
+
+// Values returns every non-empty value: the plain string or the values of
+// the mapping in language order.
+func (l Localized) Values() []string {
+	var values []string
+	if l.Value != "" {
+		values = append(values, l.Value)
+	}
+	for _, code := range l.Langs() {
+		if text := l.ByLang[code]; text != "" {
+			values = append(values, text)
+		}
+	}
+	return values
+}
+
+// Langs returns the language codes of the mapping form, sorted.
+func (l Localized) Langs() []string {
+	codes := make([]string, 0, len(l.ByLang))
+	for code := range l.ByLang {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+// IsLocalized reports whether the value uses the mapping form.
+func (l Localized) IsLocalized() bool {
+	return l.ByLang != nil
+}
+
+func (l *Localized) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.MappingNode {
+		*l = Localized{ByLang: make(map[string]string)}
+		return node.Decode(&l.ByLang)
+	}
+	*l = Localized{}
+	return node.Decode(&l.Value)
+}
+
+func (l Localized) MarshalYAML() (any, error) {
+	if l.IsLocalized() {
+		return l.ByLang, nil
+	}
+	return l.Value, nil
+}
+
+func (l Localized) MarshalJSON() ([]byte, error) {
+	if l.IsLocalized() {
+		return json.Marshal(l.ByLang)
+	}
+	return json.Marshal(l.Value)
+}
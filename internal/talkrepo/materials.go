@@ -0,0 +1,107 @@
+package talkrepo
+
+import (
+	"io/fs"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Kinds of material found in talk directories.
+const (
+	MaterialGo        = "go"        // Go modules (go.mod)
+	MaterialTerraform = "terraform" // Terraform configurations (*.tf)
+	MaterialHelm      = "helm"      // Helm charts (Chart.yaml)
+	MaterialKustomize = "kustomize" // Flux or Kustomize manifests (kustomization.yaml)
+	MaterialMakefile  = "makefile"  // Makefile at the top of the talk directory
+)
+
+// MaterialKinds lists the kinds of material in the order they are shown.
+var MaterialKinds = []string{MaterialGo, MaterialTerraform, MaterialHelm, MaterialKustomize, MaterialMakefile}
+
+// Material is a kind of material a talk directory contains besides its
+// metadata and READMEs.
+type Material struct {
+	Kind  string
+	Paths []string // relative to the talk directory, sorted: the outermost directories holding the material, or the Makefile
+}
+
+// skipDirs are directories never searched for materials: dependencies and
+// tool caches that carry go.mod or *.tf files of their own.
+var skipDirs = map[string]bool{"node_modules": true, "vendor": true}
+
+// FindMaterials returns the materials of the talk directory at dir
+// (relative to root), in the order of MaterialKinds. Hidden directories
+// are skipped, and so are unreadable ones.
+func FindMaterials(root, dir string) []Material {
+	found := make(map[string]map[string]bool) // kind -> directories
+	add := func(kind, p string) {
+		if found[kind] == nil {
+			found[kind] = make(map[string]bool)
+		}
+		found[kind][p] = true
+	}
+
+	base := filepath.Join(root, dir)
+	filepath.WalkDir(base, func(file string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return fs.SkipDir
+		}
+		rel, err := filepath.Rel(base, file)
+		if err != nil {
+			return nil
+		}
+		rel = filepath.ToSlash(rel)
+		name := entry.Name()
+
+		if entry.IsDir() {
+			if rel != "." && (name[0] == '.' || skipDirs[name]) {
+				return fs.SkipDir
+			}
+			return nil
+		}
+
+		parent := path.Dir(rel)
+		switch {
+		case name == "go.mod":
+			add(MaterialGo, parent)
+		case strings.HasSuffix(name, ".tf"):
+			add(MaterialTerraform, parent)
+		case name == "Chart.yaml":
+			add(MaterialHelm, parent)
+		case name == "kustomization.yaml" || name == "kustomization.yml" || name == "Kustomization":
+			add(MaterialKustomize, parent)
+		case name == "Makefile" && parent == ".":
+			add(MaterialMakefile, rel)
+		}
+		return nil
+	})
+
+	var materials []Material
+	for _, kind := range MaterialKinds {
+		if dirs := found[kind]; len(dirs) > 0 {
+			materials = append(materials, Material{Kind: kind, Paths: outermost(dirs)})
+		}
+	}
+	return materials
+}
+
+// outermost returns the sorted paths of dirs that are not inside another
+// one of them, so a chart's subcharts or the kustomizations an overlay
+// includes count once.
+func outermost(dirs map[string]bool) []string {
+	var paths []string
+	for dir := range dirs {
+		inside := false
+		for p := dir; p != "." && !inside; {
+			p = path.Dir(p)
+			inside = dirs[p]
+		}
+		if !inside {
+			paths = append(paths, dir)
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
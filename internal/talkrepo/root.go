@@ -0,0 +1,54 @@
+package talkrepo
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+)
+
+// ConfigFile is the repository configuration file, which also marks the
+// repository root.
+const ConfigFile = "talks.yaml"
+
+// ErrRootNotFound is returned by FindRoot when no repository root is found.
+var ErrRootNotFound = errors.New("could not find the talks repository root (no talks.yaml, or go.mod next to a year directory)")
+
+// FindRoot walks up from start to the repository root: the nearest directory
+// containing talks.yaml, or containing go.mod alongside at least one year
+// directory. The go.mod check alone is not enough because demo apps inside
+// talk directories carry their own go.mod files.
+func FindRoot(start string) (string, error) {
+	dir, err := filepath.Abs(start)
+	if err != nil {
+		return "", err
+	}
+
+	for {
+		if isRoot(dir) {
+			return dir, nil
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", ErrRootNotFound
+		}
+		dir = parent
+	}
+}
+
+func isRoot(dir string) bool {
+	if exists(filepath.Join(dir, ConfigFile)) {
+		return true
+	}
+	if !exists(filepath.Join(dir, "go.mod")) {
+		return false
+	}
+
+	years, err := FindYears(dir)
+	return err == nil && len(years) > 0
+}
+
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
@@ -0,0 +1,182 @@
+// Package talkrepo discovers and loads the talks stored in this repository.
+//
+// Talks live in year directories (2025/, 2026/, ...) and each talk directory
+// carries a metadata.yaml file describing it. Every command reads talks
+// through this package so they all agree on the schema and on which
+// directories count as talks.
+package talkrepo
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// MetadataFile is the name of the metadata file inside each talk directory.
+const MetadataFile = "metadata.yaml"
+
+// Metadata is the content of a talk's metadata.yaml file.
+type Metadata struct {
+	Title       Localized `yaml:"title"`
+	Date        string    `yaml:"date"`
+	Event       string    `yaml:"event"`
+	Topics      []string  `yaml:"topics"`
+	Description Localized `yaml:"description"`
+	SlidesURL   string    `yaml:"slides_url"`
+	VideoURL    string    `yaml:"video_url"`
+	Time        string    `yaml:"time"`     // start time, HH:MM
+	Timezone    string    `yaml:"timezone"` // IANA time zone of Time, e.g. Europe/Madrid
+	Location    string    `yaml:"location"` // venue address
+}
+
+// Talk is a talk directory together with its parsed metadata.
+type Talk struct {
+	Metadata
+	Path string // relative to the repository root, e.g. 2025/oct-30th-intro-to-flux-with-eks
+	Year string
+
+	Materials []Material // code, infrastructure and manifests in the talk directory
+}
+
+// Errors returned (wrapped in a *TalkError) when a talk cannot be loaded.
+var (
+	ErrMissingMetadata = errors.New("missing metadata.yaml")
+	ErrReadMetadata    = errors.New("cannot read metadata.yaml")
+	ErrParseMetadata   = errors.New("cannot parse metadata.yaml")
+)
+
+// TalkError reports a problem with a single talk directory.
+type TalkError struct {
+	Path string // talk directory, relative to the repository root
+	Kind error  // one of ErrMissingMetadata, ErrReadMetadata, ErrParseMetadata
+	Err  error  // underlying error, if any
+}
+
+func (e *TalkError) Error() string {
+	if e.Err == nil {
+		return fmt.Sprintf("%s: %v", e.Path, e.Kind)
+	}
+	return fmt.Sprintf("%s: %v: %v", e.Path, e.Kind, e.Err)
+}
+
+// Is lets errors.Is match a TalkError against its Kind.
+func (e *TalkError) Is(target error) bool {
+	return target == e.Kind
+}
+
+func (e *TalkError) Unwrap() error {
+	return e.Err
+}
+
+// FindYears returns the year directories (four-digit names) under root,
+// in ascending order.
+func FindYears(root string) ([]string, error) {
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return nil, err
+	}
+
+	var years []string
+	for _, entry := range entries {
+		if !entry.IsDir() || len(entry.Name()) != 4 {
+			continue
+		}
+		if _, err := time.Parse("2006", entry.Name()); err == nil {
+			years = append(years, entry.Name())
+		}
+	}
+
+	sort.Strings(years)
+	return years, nil
+}
+
+// FindTalkDirs returns every talk directory under root, relative to root,
+// ordered by year and then by directory name. Hidden directories are skipped.
+func FindTalkDirs(root string) ([]string, error) {
+	years, err := FindYears(root)
+	if err != nil {
+		return nil, err
+	}
+
+	var dirs []string
+	for _, year := range years {
+		entries, err := os.ReadDir(filepath.Join(root, year))
+		if err != nil {
+			return nil, err
+		}
+
+		for _, entry := range entries {
+			if !entry.IsDir() || entry.Name()[0] == '.' {
+				continue
+			}
+			dirs = append(dirs, filepath.Join(year, entry.Name()))
+		}
+	}
+
+	return dirs, nil
+}
+
+// LoadTalk reads the metadata of the talk directory at path (relative to
+// root) and takes an inventory of its materials. Failures are returned as
+// *TalkError.
+func LoadTalk(root, path string) (Talk, error) {
+	talk := Talk{
+		Path: filepath.ToSlash(path),
+		Year: filepath.Base(filepath.Dir(path)),
+	}
+
+	data, err := os.ReadFile(filepath.Join(root, path, MetadataFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return talk, &TalkError{Path: talk.Path, Kind: ErrMissingMetadata}
+	}
+	if err != nil {
+		return talk, &TalkError{Path: talk.Path, Kind: ErrReadMetadata, Err: err}
+	}
+
+	if err := yaml.Unmarshal(data, &talk.Metadata); err != nil {
+		return talk, &TalkError{Path: talk.Path, Kind: ErrParseMetadata, Err: err}
+	}
+
+	talk.Materials = FindMaterials(root, path)
+	return talk, nil
+}
+
+// Load returns every talk under root that has valid metadata, along with a
+// *TalkError for each talk directory that could not be loaded. The returned
+// error is only set when the repository itself cannot be scanned.
+func Load(root string) ([]Talk, []*TalkError, error) {
+	dirs, err := FindTalkDirs(root)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var talks []Talk
+	var talkErrs []*TalkError
+	for _, dir := range dirs {
+		talk, err := LoadTalk(root, dir)
+		if err != nil {
+			var talkErr *TalkError
+			if errors.As(err, &talkErr) {
+				talkErrs = append(talkErrs, talkErr)
+				continue
+			}
+			return nil, nil, err
+		}
+		talks = append(talks, talk)
+	}
+
+	return talks, talkErrs, nil
+}
+
+// SortByDateDesc sorts talks newest first, keeping directory order for talks
+// on the same date.
+func SortByDateDesc(talks []Talk) {
+	sort.SliceStable(talks, func(i, j int) bool {
+		return talks[i].Date > talks[j].Date
+	})
+}
@@ -0,0 +1,169 @@
+// Package topics loads the topic registry (topics.yaml), which maps the
+// free-form topics of metadata.yaml to canonical names.
+//
+// Each registry entry has a canonical name, the aliases that mean the same
+// topic, an optional parent topic used to group related topics, and display
+// names per language. Lookups ignore case.
+package topics
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Topic is an entry of the registry.
+type Topic struct {
+	Name    string            `yaml:"name"`    // canonical name
+	Aliases []string          `yaml:"aliases"` // other spellings of the topic
+	Parent  string            `yaml:"parent"`  // canonical name of the parent topic, if any
+	Names   map[string]string `yaml:"names"`   // display names by language code
+}
+
+// Registry is the content of the topics file. The zero value is an empty
+// registry, which leaves every topic as written.
+type Registry struct {
+	Topics []Topic `yaml:"topics"`
+
+	index map[string]int // lowercase name or alias -> index in Topics
+}
+
+// Load reads the registry at path. A missing file is not an error and
+// yields an empty registry.
+func Load(path string) (*Registry, error) {
+	r := &Registry{}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return r, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := yaml.Unmarshal(data, r); err != nil {
+		return nil, err
+	}
+	if err := r.build(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// build indexes names and aliases and validates the entries.
+func (r *Registry) build() error {
+	r.index = make(map[string]int)
+	add := func(key string, i int) error {
+		k := strings.ToLower(strings.TrimSpace(key))
+		if k == "" {
+			return fmt.Errorf("topics[%d]: empty name or alias", i)
+		}
+		if j, ok := r.index[k]; ok && j != i {
+			return fmt.Errorf("topics[%d]: %q is already used by %q", i, key, r.Topics[j].Name)
+		}
+		r.index[k] = i
+		return nil
+	}
+
+	for i, topic := range r.Topics {
+		if err := add(topic.Name, i); err != nil {
+			return err
+		}
+		for _, alias := range topic.Aliases {
+			if err := add(alias, i); err != nil {
+				return err
+			}
+		}
+	}
+
+	for i, topic := range r.Topics {
+		if topic.Parent == "" {
+			continue
+		}
+		parent, ok := r.Lookup(topic.Parent)
+		if !ok {
+			return fmt.Errorf("topics[%d]: unknown parent %q", i, topic.Parent)
+		}
+		if parent.Name != topic.Parent {
+			return fmt.Errorf("topics[%d]: parent must be the canonical name %q, not %q", i, parent.Name, topic.Parent)
+		}
+	}
+
+	// Parents must not loop back to the topic
+	for i, topic := range r.Topics {
+		seen := map[string]bool{topic.Name: true}
+		for p := topic.Parent; p != ""; p = r.Parent(p) {
+			if seen[p] {
+				return fmt.Errorf("topics[%d]: parent cycle through %q", i, p)
+			}
+			seen[p] = true
+		}
+	}
+
+	return nil
+}
+
+// Len returns the number of topics in the registry.
+func (r *Registry) Len() int {
+	return len(r.Topics)
+}
+
+// Lookup returns the registry entry whose name or alias matches topic.
+func (r *Registry) Lookup(topic string) (Topic, bool) {
+	i, ok := r.index[strings.ToLower(strings.TrimSpace(topic))]
+	if !ok {
+		return Topic{}, false
+	}
+	return r.Topics[i], true
+}
+
+// Canonical returns the canonical name of topic, or topic itself when it is
+// not in the registry.
+func (r *Registry) Canonical(topic string) string {
+	if t, ok := r.Lookup(topic); ok {
+		return t.Name
+	}
+	return topic
+}
+
+// Normalize maps topics to their canonical names, dropping empty topics and
+// duplicates while keeping the original order.
+func (r *Registry) Normalize(topics []string) []string {
+	var out []string
+	seen := make(map[string]bool)
+	for _, topic := range topics {
+		if strings.TrimSpace(topic) == "" {
+			continue
+		}
+		name := r.Canonical(topic)
+		if !seen[name] {
+			seen[name] = true
+			out = append(out, name)
+		}
+	}
+	return out
+}
+
+// Parent returns the canonical name of the parent of topic, or "".
+func (r *Registry) Parent(topic string) string {
+	if t, ok := r.Lookup(topic); ok {
+		return t.Parent
+	}
+	return ""
+}
+
+// DisplayName returns the name of topic for the language lang: the
+// registry's display name for that language, or the canonical name.
+func (r *Registry) DisplayName(topic, lang string) string {
+	t, ok := r.Lookup(topic)
+	if !ok {
+		return topic
+	}
+	if name := t.Names[lang]; name != "" {
+		return name
+	}
+	return t.Name
+}